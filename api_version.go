@@ -0,0 +1,100 @@
+package goshopify
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// Shopify supports every stable api version for at least 12 months after
+	// its release
+	apiVersionSupportMonths = 12
+
+	// NewClient warns about versions reaching their end of support within 3
+	// months
+	apiVersionSunsetWarningMonths = 3
+)
+
+// warnedApiVersions holds the versions already warned about, so that
+// clients created per request or per shop don't repeat the warning
+var warnedApiVersions sync.Map
+
+// validApiVersion reports whether a version can be pinned with WithVersion
+func validApiVersion(version string) bool {
+	if version == UnstableApiVersion {
+		return true
+	}
+	if !apiVersionRegex.MatchString(version) {
+		return false
+	}
+	_, err := time.Parse("2006-01", version)
+	return err == nil
+}
+
+// ApiVersionSupport is the support window of a stable YYYY-MM api version.
+// See: https://shopify.dev/docs/api/usage/versioning
+type ApiVersionSupport struct {
+	Version string
+
+	// ReleasedAt is the first day of the month of the version.
+	ReleasedAt time.Time
+
+	// SunsetAt is when the version is no longer supported, 12 months after its
+	// release. Requests made with an unsupported version are served by the
+	// oldest supported version instead.
+	SunsetAt time.Time
+
+	// Supported reports whether the version is released and not sunset.
+	Supported bool
+
+	// NearingSunset reports whether the supported version reaches its sunset
+	// within 3 months.
+	NearingSunset bool
+}
+
+// GetApiVersionSupport returns the support window of a YYYY-MM api version at
+// the time now.
+func GetApiVersionSupport(version string, now time.Time) (ApiVersionSupport, error) {
+	support := ApiVersionSupport{Version: version}
+	if !apiVersionRegex.MatchString(version) {
+		return support, fmt.Errorf("invalid api version %q, expected a YYYY-MM version", version)
+	}
+	releasedAt, err := time.Parse("2006-01", version)
+	if err != nil {
+		return support, fmt.Errorf("invalid api version %q, expected a YYYY-MM version", version)
+	}
+
+	support.ReleasedAt = releasedAt
+	support.SunsetAt = releasedAt.AddDate(0, apiVersionSupportMonths, 0)
+	support.Supported = !now.Before(support.ReleasedAt) && now.Before(support.SunsetAt)
+	support.NearingSunset = support.Supported && !now.Before(support.SunsetAt.AddDate(0, -apiVersionSunsetWarningMonths, 0))
+	return support, nil
+}
+
+// warnApiVersionSupport logs a warning when the pinned version of the client
+// isn't supported at the time now or nears its sunset, once per version
+func (c *Client) warnApiVersionSupport(now time.Time) {
+	support, err := GetApiVersionSupport(c.apiVersion, now)
+	if err != nil {
+		// unversioned and unstable clients have no support window
+		return
+	}
+	if support.Supported && !support.NearingSunset {
+		return
+	}
+	if _, warned := warnedApiVersions.LoadOrStore(support.Version, struct{}{}); warned {
+		return
+	}
+
+	switch {
+	case now.Before(support.ReleasedAt):
+		c.log.Warnf("api version %s is not released yet", support.Version)
+	case !support.Supported:
+		c.log.Warnf("api version %s is no longer supported since %s, requests are served by the oldest supported version",
+			support.Version, support.SunsetAt.Format("2006-01-02"))
+	case support.NearingSunset:
+		c.log.Warnf("api version %s is no longer supported after %s, upgrade to a newer version",
+			support.Version, support.SunsetAt.AddDate(0, 0, -1).Format("2006-01-02"))
+	}
+}
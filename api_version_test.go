@@ -0,0 +1,94 @@
+package goshopify
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetApiVersionSupport(t *testing.T) {
+	now := time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		version       string
+		supported     bool
+		nearingSunset bool
+	}{
+		{"2024-10", false, false},
+		{"2024-07", true, false},
+		{"2024-01", true, false},
+		{"2023-10", true, true},
+		{"2023-09", true, true},
+		{"2023-08", false, false},
+		{"2022-04", false, false},
+	}
+
+	for _, c := range cases {
+		support, err := GetApiVersionSupport(c.version, now)
+		if err != nil {
+			t.Errorf("GetApiVersionSupport(%s) returned error: %v", c.version, err)
+			continue
+		}
+		if support.Supported != c.supported || support.NearingSunset != c.nearingSunset {
+			t.Errorf("GetApiVersionSupport(%s) = %+v, expected supported %t, nearing sunset %t",
+				c.version, support, c.supported, c.nearingSunset)
+		}
+	}
+
+	support, _ := GetApiVersionSupport("2024-04", now)
+	if expected := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC); !support.SunsetAt.Equal(expected) {
+		t.Errorf("GetApiVersionSupport(2024-04).SunsetAt = %s, expected %s", support.SunsetAt, expected)
+	}
+
+	for _, version := range []string{"unstable", "stable", "2024-13", "2024-4"} {
+		if _, err := GetApiVersionSupport(version, now); err == nil {
+			t.Errorf("GetApiVersionSupport(%s) should return an error", version)
+		}
+	}
+}
+
+func TestClientWarnApiVersionSupport(t *testing.T) {
+	now := time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]string{
+		"2024-07":  "",
+		"2023-10":  "[WARN] api version 2023-10 is no longer supported after 2024-09-30, upgrade to a newer version",
+		"2023-04":  "[WARN] api version 2023-04 is no longer supported since 2024-04-01, requests are served by the oldest supported version",
+		"2025-01":  "[WARN] api version 2025-01 is not released yet",
+		"unstable": "",
+	}
+
+	for version, expected := range cases {
+		out := &bytes.Buffer{}
+		c := MustNewClient(app, "fooshop", "abcd", WithVersion(version),
+			WithLogger(&LeveledLogger{Level: LevelWarn, stderrOverride: out, stdoutOverride: out}))
+		out.Reset()
+		warnedApiVersions.Delete(version)
+
+		c.warnApiVersionSupport(now)
+		if actual := strings.TrimSpace(out.String()); actual != expected {
+			t.Errorf("version %s warned %q, expected %q", version, actual, expected)
+		}
+
+		// each version is warned about once
+		out.Reset()
+		c.warnApiVersionSupport(now)
+		if out.Len() != 0 {
+			t.Errorf("version %s warned again: %q", version, out.String())
+		}
+	}
+}
+
+func TestNewClientWarnsApiVersionSunset(t *testing.T) {
+	// a version released 11 months ago reaches its sunset next month
+	version := time.Now().AddDate(0, -11, 0).Format("2006-01")
+	warnedApiVersions.Delete(version)
+
+	out := &bytes.Buffer{}
+	MustNewClient(app, "fooshop", "abcd", WithVersion(version),
+		WithLogger(&LeveledLogger{Level: LevelWarn, stderrOverride: out, stdoutOverride: out}))
+	if !strings.Contains(out.String(), "api version "+version+" is no longer supported after") {
+		t.Errorf("NewClient logged %q, expected a sunset warning", out.String())
+	}
+}
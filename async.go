@@ -0,0 +1,146 @@
+package goshopify
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	// how often a 202 Accepted resource is polled when Shopify sends no Retry-After
+	defaultAsyncPollInterval = time.Second
+
+	// how long 202 Accepted responses are polled at most, defaults to five minutes
+	defaultAsyncPollTimeout = 5 * time.Minute
+
+	// maximum number of consecutive redirects followed
+	maxRedirects = 10
+)
+
+// stopAtRedirects returns client, or a copy of it when it follows redirects
+// itself, so that redirects reach awaitResponse. The http.Client would follow
+// them with the access token, whatever host they point at.
+func stopAtRedirects(client *http.Client) *http.Client {
+	if client.CheckRedirect != nil {
+		return client
+	}
+
+	stopping := *client
+	stopping.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &stopping
+}
+
+// awaitResponse resolves asynchronous responses into the final one. A 303 See
+// Other, and any other redirect of a GET request, is followed with a GET of
+// its Location. A 202 Accepted is polled with GET requests of its Location,
+// or of the request itself for GET requests, after waiting for Retry-After,
+// until Shopify stops answering 202, the request's context is done or the
+// poll timeout is reached, in which case the last 202 response is returned.
+// Other responses are returned unchanged.
+func (c *Client) awaitResponse(req *http.Request, resp *http.Response, rateLimiter RateLimiter) (*http.Response, error) {
+	redirects := 0
+	var pollStart time.Time
+	for {
+		var wait time.Duration
+		location := resp.Header.Get("Location")
+
+		switch resp.StatusCode {
+		case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+			// the body of other requests isn't sent again
+			if req.Method != http.MethodGet {
+				return resp, nil
+			}
+			fallthrough
+		case http.StatusSeeOther:
+			// without a Location the response is reported as an error
+			if location == "" || redirects >= maxRedirects {
+				return resp, nil
+			}
+			redirects++
+		case http.StatusAccepted:
+			if location == "" {
+				if req.Method != http.MethodGet {
+					// nothing to poll without repeating the request
+					return resp, nil
+				}
+				location = req.URL.String()
+			}
+			wait = retryAfter(resp.Header)
+			if wait <= 0 {
+				wait = c.asyncPollInterval
+			}
+			if wait <= 0 {
+				wait = defaultAsyncPollInterval
+			}
+
+			timeout := c.asyncPollTimeout
+			if timeout <= 0 {
+				timeout = defaultAsyncPollTimeout
+			}
+			if pollStart.IsZero() {
+				pollStart = time.Now()
+			} else if time.Since(pollStart)+wait > timeout {
+				c.log.Warnf("%s still accepted after polling for %s, giving up", location, timeout)
+				return resp, nil
+			}
+			redirects = 0
+		default:
+			return resp, nil
+		}
+
+		next, err := newFollowRequest(req, location)
+		// drain the response so that its connection can be reused
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		if rateLimiter != nil {
+			if err := rateLimiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		c.logRequest(next)
+		resp, err = c.Client.Do(next)
+		c.logResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+		c.observeResponse(next, resp, rateLimiter)
+		req = next
+	}
+}
+
+// newFollowRequest creates the GET request following a redirect or 202 response to
+// req. Credentials are only sent to the shop the original request was sent to.
+func newFollowRequest(req *http.Request, location string) (*http.Request, error) {
+	u, err := req.URL.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := http.NewRequestWithContext(req.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	next.Header = req.Header.Clone()
+	next.Header.Del("Content-Type")
+	next.Header.Del("Content-Length")
+	if u.Host != req.URL.Host {
+		next.Header.Del("Authorization")
+		next.Header.Del("X-Shopify-Access-Token")
+		next.Header.Del(storefrontTokenHeader)
+		next.Header.Del(storefrontBuyerIPHeader)
+	}
+	return next, nil
+}
@@ -0,0 +1,209 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestClientFollowsSeeOther(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/themes.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusSeeOther, "")
+			resp.Header.Set("Location", fmt.Sprintf("/%s/themes/1.json", client.pathPrefix))
+			return resp, nil
+		})
+
+	var followed *http.Request
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/themes/1.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			followed = req
+			return httpmock.NewStringResponse(200, `{"theme":{"id":1,"processing":false}}`), nil
+		})
+
+	theme, err := client.Theme.Create(context.Background(), Theme{Name: "new"})
+	if err != nil {
+		t.Fatalf("Theme.Create returned error: %v", err)
+	}
+	if theme.Id != 1 {
+		t.Errorf("Theme.Create returned %#v, expected the resource at the Location", theme)
+	}
+	if followed == nil || followed.Header.Get("X-Shopify-Access-Token") != "abcd" {
+		t.Errorf("the Location was not requested with the access token: %#v", followed)
+	}
+}
+
+func TestClientRedirectToOtherHost(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/themes/1.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusFound, "")
+			resp.Header.Set("Location", "https://example.com/themes/1.json")
+			return resp, nil
+		})
+
+	var followed *http.Request
+	httpmock.RegisterResponder("GET", "https://example.com/themes/1.json",
+		func(req *http.Request) (*http.Response, error) {
+			followed = req
+			return httpmock.NewStringResponse(200, `{"theme":{"id":1}}`), nil
+		})
+
+	if _, err := client.Theme.Get(context.Background(), 1, nil); err != nil {
+		t.Fatalf("Theme.Get returned error: %v", err)
+	}
+	if followed == nil {
+		t.Fatal("the Location was not requested")
+	}
+	if token := followed.Header.Get("X-Shopify-Access-Token"); token != "" {
+		t.Errorf("the access token was sent to another host: %s", token)
+	}
+}
+
+func TestNewClientKeepsCheckRedirect(t *testing.T) {
+	httpClient := &http.Client{}
+	c := MustNewClient(app, "fooshop", "abcd", WithHTTPClient(httpClient))
+	if c.Client == httpClient || c.Client.CheckRedirect == nil || httpClient.CheckRedirect != nil {
+		t.Error("NewClient should stop at redirects without changing the given http client")
+	}
+
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error { return nil }
+	if c := MustNewClient(app, "fooshop", "abcd", WithHTTPClient(httpClient)); c.Client != httpClient {
+		t.Error("NewClient should keep an http client with its own CheckRedirect")
+	}
+}
+
+func TestClientSeeOtherWithoutLocation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/themes/1.json", client.pathPrefix),
+		httpmock.NewStringResponder(http.StatusSeeOther, ""))
+
+	_, err := client.Theme.Get(context.Background(), 1, nil)
+	var respErr ResponseError
+	if !errors.As(err, &respErr) || respErr.Status != http.StatusSeeOther {
+		t.Errorf("Theme.Get returned %v, expected a 303 ResponseError", err)
+	}
+}
+
+func TestClientPollsAccepted(t *testing.T) {
+	setup()
+	defer teardown()
+	client.asyncPollInterval = time.Millisecond
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/products/1/images.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusAccepted, `{"image":{"id":2}}`)
+			resp.Header.Set("Location", fmt.Sprintf("https://fooshop.myshopify.com/%s/products/1/images/2.json", client.pathPrefix))
+			resp.Header.Set("Retry-After", "0.001")
+			return resp, nil
+		})
+
+	polls := 0
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/products/1/images/2.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			polls++
+			if polls < 3 {
+				return httpmock.NewStringResponse(http.StatusAccepted, `{"image":{"id":2}}`), nil
+			}
+			return httpmock.NewStringResponse(200, `{"image":{"id":2,"src":"https://cdn.shopify.com/image.png"}}`), nil
+		})
+
+	image, err := client.Image.Create(context.Background(), 1, Image{Attachment: "aGVsbG8="})
+	if err != nil {
+		t.Fatalf("Image.Create returned error: %v", err)
+	}
+	if polls != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+	if image.Src != "https://cdn.shopify.com/image.png" {
+		t.Errorf("Image.Create returned %#v, expected the finished image", image)
+	}
+}
+
+func TestClientPollsAcceptedUntilContextDone(t *testing.T) {
+	setup()
+	defer teardown()
+	client.asyncPollInterval = time.Millisecond
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/themes/1.json", client.pathPrefix),
+		httpmock.NewStringResponder(http.StatusAccepted, `{"theme":{"id":1,"processing":true}}`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Theme.Get(ctx, 1, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Theme.Get returned %v, expected context.DeadlineExceeded", err)
+	}
+}
+
+func TestClientPollsAcceptedUntilTimeout(t *testing.T) {
+	setup()
+	defer teardown()
+	client.asyncPollInterval = time.Millisecond
+	client.asyncPollTimeout = 20 * time.Millisecond
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/themes/1.json", client.pathPrefix),
+		httpmock.NewStringResponder(http.StatusAccepted, `{"theme":{"id":1,"processing":true}}`))
+
+	// the last 202 response is returned once polling takes too long
+	theme, err := client.Theme.Get(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatalf("Theme.Get returned error: %v", err)
+	}
+	if !theme.Processing {
+		t.Errorf("Theme.Get returned %#v, expected the accepted theme", theme)
+	}
+}
+
+func TestClientAcceptedWithoutLocation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/themes.json", client.pathPrefix),
+		httpmock.NewStringResponder(http.StatusAccepted, `{"theme":{"id":1,"processing":true}}`))
+
+	// requests other than GET are not repeated
+	theme, err := client.Theme.Create(context.Background(), Theme{Name: "new"})
+	if err != nil {
+		t.Fatalf("Theme.Create returned error: %v", err)
+	}
+	if theme.Id != 1 || !theme.Processing {
+		t.Errorf("Theme.Create returned %#v", theme)
+	}
+}
+
+func TestNewFollowRequest(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://fooshop.myshopify.com/admin/themes.json", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Shopify-Access-Token", "abcd")
+
+	next, err := newFollowRequest(req, "/admin/themes/1.json")
+	if err != nil {
+		t.Fatalf("newFollowRequest returned error: %v", err)
+	}
+	if next.Method != "GET" || next.URL.String() != "https://fooshop.myshopify.com/admin/themes/1.json" ||
+		next.Header.Get("Content-Type") != "" || next.Header.Get("X-Shopify-Access-Token") != "abcd" {
+		t.Errorf("unexpected follow request %s %s %v", next.Method, next.URL, next.Header)
+	}
+
+	// credentials are not sent to other hosts
+	next, err = newFollowRequest(req, "https://example.com/themes/1.json")
+	if err != nil {
+		t.Fatalf("newFollowRequest returned error: %v", err)
+	}
+	if next.Header.Get("X-Shopify-Access-Token") != "" {
+		t.Error("the access token should not be sent to another host")
+	}
+}
@@ -0,0 +1,231 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrTokenNotFound is returned by a TokenStore when it has no access token for
+// a shop, e.g. because the app has not been installed yet.
+var ErrTokenNotFound = errors.New("access token not found")
+
+// TokenStore stores the access tokens of the shops an app is installed on.
+// Shops are identified by their full myshopify domain, see ShopFullName.
+// Implementations must be safe for concurrent use.
+type TokenStore interface {
+	// GetToken returns the shop's access token or ErrTokenNotFound.
+	GetToken(ctx context.Context, shop string) (string, error)
+	SetToken(ctx context.Context, shop, token string) error
+	DeleteToken(ctx context.Context, shop string) error
+}
+
+// MemoryTokenStore is a TokenStore keeping tokens in memory.
+type MemoryTokenStore struct {
+	tokenStore
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokenStore{newMemoryValueStore[string](ErrTokenNotFound)}}
+}
+
+// FileTokenStore is a TokenStore keeping tokens in a JSON file, readable only
+// by the current user. It is meant for development and small deployments,
+// every change rewrites the whole file.
+type FileTokenStore struct {
+	tokenStore
+}
+
+// NewFileTokenStore returns a FileTokenStore using the file at path, which is
+// created on the first SetToken.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{tokenStore{newFileValueStore[string](path, ErrTokenNotFound)}}
+}
+
+// tokenStore implements TokenStore, keeping tokens by full shop domain.
+type tokenStore struct {
+	tokens *valueStore[string]
+}
+
+// GetToken implements TokenStore.
+func (s tokenStore) GetToken(_ context.Context, shop string) (string, error) {
+	return s.tokens.load(ShopFullName(shop))
+}
+
+// SetToken implements TokenStore.
+func (s tokenStore) SetToken(_ context.Context, shop, token string) error {
+	return s.tokens.save(ShopFullName(shop), token)
+}
+
+// DeleteToken implements TokenStore.
+func (s tokenStore) DeleteToken(_ context.Context, shop string) error {
+	return s.tokens.delete(ShopFullName(shop))
+}
+
+// ClientManager lazily creates and caches a Client per shop for an app, with
+// access tokens loaded from a TokenStore. It is safe for concurrent use.
+//
+// Clients unused for IdleTimeout are evicted, and a shop's client is
+// invalidated as soon as one of its requests returns 401 Unauthorized, e.g.
+// because the app was uninstalled, after which OnUnauthorized is called so
+// the app can ask the merchant to reinstall it.
+type ClientManager struct {
+	// IdleTimeout is how long a client may go unused before it is evicted,
+	// defaults to 30 minutes. Zero never evicts clients.
+	IdleTimeout time.Duration
+
+	// OnUnauthorized, if set, is called with the shop's domain after one of
+	// its requests returned 401 Unauthorized.
+	OnUnauthorized func(shop string)
+
+	// ShopOptions, if set, returns additional options for a shop's client,
+	// e.g. a rate limiter which must not be shared between shops.
+	ShopOptions func(shop string) []Option
+
+	app   App
+	store TokenStore
+	opts  []Option
+
+	mu        sync.Mutex
+	clients   map[string]*managedClient
+	lastSweep time.Time
+
+	// Internal testing use only.
+	now func() time.Time
+}
+
+type managedClient struct {
+	client   *Client
+	lastUsed time.Time
+}
+
+const defaultClientIdleTimeout = 30 * time.Minute
+
+// NewClientManager returns a ClientManager for the app. The options, such as
+// WithVersion, WithRetry or WithLogger, are applied to every client.
+func NewClientManager(app App, store TokenStore, opts ...Option) *ClientManager {
+	return &ClientManager{
+		IdleTimeout: defaultClientIdleTimeout,
+		app:         app,
+		store:       store,
+		opts:        opts,
+		clients:     make(map[string]*managedClient),
+		now:         time.Now,
+	}
+}
+
+// Client returns the client of the shop, creating it with the shop's token
+// from the TokenStore if it isn't cached. The store's ErrTokenNotFound is
+// returned for shops the app isn't installed on.
+func (m *ClientManager) Client(ctx context.Context, shop string) (*Client, error) {
+	shop = ShopFullName(shop)
+
+	m.mu.Lock()
+	m.sweep()
+	if managed, ok := m.clients[shop]; ok {
+		managed.lastUsed = m.now()
+		m.mu.Unlock()
+		return managed.client, nil
+	}
+	m.mu.Unlock()
+
+	// don't hold the lock while the store is queried
+	token, err := m.store.GetToken(ctx, shop)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := make([]Option, 0, len(m.opts)+2)
+	opts = append(opts, m.opts...)
+	if m.ShopOptions != nil {
+		opts = append(opts, m.ShopOptions(shop)...)
+	}
+
+	client, err := NewClient(m.app, shop, token, opts...)
+	if err != nil {
+		return nil, err
+	}
+	WithMiddleware(m.invalidateOnUnauthorized(shop, client))(client)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// another goroutine may have created the client in the meantime
+	if managed, ok := m.clients[shop]; ok {
+		managed.lastUsed = m.now()
+		return managed.client, nil
+	}
+	m.clients[shop] = &managedClient{client: client, lastUsed: m.now()}
+	return client, nil
+}
+
+// Invalidate removes the shop's cached client, the next call to Client
+// creates a new one with the token currently in the TokenStore.
+func (m *ClientManager) Invalidate(shop string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.clients, ShopFullName(shop))
+}
+
+// EvictIdle removes every client unused for longer than IdleTimeout and
+// returns how many were removed. Idle clients are also evicted periodically
+// while Client is called.
+func (m *ClientManager) EvictIdle() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.evictIdle()
+}
+
+// sweep evicts idle clients at most once per IdleTimeout. Callers must hold
+// m.mu.
+func (m *ClientManager) sweep() {
+	if m.IdleTimeout <= 0 || m.now().Sub(m.lastSweep) < m.IdleTimeout {
+		return
+	}
+	m.evictIdle()
+}
+
+// evictIdle removes idle clients. Callers must hold m.mu.
+func (m *ClientManager) evictIdle() int {
+	now := m.now()
+	m.lastSweep = now
+	if m.IdleTimeout <= 0 {
+		return 0
+	}
+
+	evicted := 0
+	for shop, managed := range m.clients {
+		if now.Sub(managed.lastUsed) > m.IdleTimeout {
+			delete(m.clients, shop)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// invalidateOnUnauthorized returns the middleware invalidating the shop's
+// client when one of its requests returns 401 Unauthorized. A client which
+// was already replaced doesn't invalidate its successor.
+func (m *ClientManager) invalidateOnUnauthorized(shop string, client *Client) Middleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(req *http.Request, v interface{}) (http.Header, error) {
+			headers, err := next(req, v)
+
+			if errors.Is(err, ErrUnauthorized) {
+				m.mu.Lock()
+				if managed, ok := m.clients[shop]; ok && managed.client == client {
+					delete(m.clients, shop)
+				}
+				m.mu.Unlock()
+
+				if m.OnUnauthorized != nil {
+					m.OnUnauthorized(shop)
+				}
+			}
+			return headers, err
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestTokenStoreShopNames(t *testing.T) {
+	stores := []TokenStore{NewMemoryTokenStore(), NewFileTokenStore(filepath.Join(t.TempDir(), "tokens.json"))}
+	for _, store := range stores {
+		ctx := context.Background()
+		if err := store.SetToken(ctx, "fooshop", "abcd"); err != nil {
+			t.Fatalf("SetToken returned error: %v", err)
+		}
+
+		// shops are normalized to their full domain
+		if token, err := store.GetToken(ctx, "fooshop.myshopify.com"); err != nil || token != "abcd" {
+			t.Errorf("%T.GetToken returned %q, %v, expected abcd", store, token, err)
+		}
+		if err := store.DeleteToken(ctx, "fooshop.myshopify.com"); err != nil {
+			t.Fatalf("DeleteToken returned error: %v", err)
+		}
+		if _, err := store.GetToken(ctx, "fooshop"); !errors.Is(err, ErrTokenNotFound) {
+			t.Errorf("%T.GetToken after DeleteToken returned %v, expected ErrTokenNotFound", store, err)
+		}
+	}
+}
+
+func TestClientManagerClient(t *testing.T) {
+	setup()
+	defer teardown()
+
+	store := NewMemoryTokenStore()
+	_ = store.SetToken(context.Background(), "fooshop", "abcd")
+
+	manager := NewClientManager(app, store, WithVersion(testApiVersion), skipApiVersionCheck(), WithHTTPClient(client.Client))
+
+	first, err := manager.Client(context.Background(), "fooshop")
+	if err != nil {
+		t.Fatalf("ClientManager.Client returned error: %v", err)
+	}
+	if first.token != "abcd" || first.apiVersion != testApiVersion {
+		t.Errorf("client created with token %q and version %q", first.token, first.apiVersion)
+	}
+
+	second, err := manager.Client(context.Background(), "fooshop.myshopify.com")
+	if err != nil {
+		t.Fatalf("ClientManager.Client returned error: %v", err)
+	}
+	if first != second {
+		t.Error("ClientManager.Client should return the cached client")
+	}
+
+	if _, err := manager.Client(context.Background(), "barshop"); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("ClientManager.Client returned %v, expected ErrTokenNotFound", err)
+	}
+
+	manager.Invalidate("fooshop")
+	third, _ := manager.Client(context.Background(), "fooshop")
+	if third == first {
+		t.Error("ClientManager.Client should create a new client after Invalidate")
+	}
+}
+
+func TestClientManagerShopOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	store := NewMemoryTokenStore()
+	_ = store.SetToken(context.Background(), "fooshop", "abcd")
+
+	limiter := NewLeakyBucket(80, 4)
+	manager := NewClientManager(app, store)
+	manager.ShopOptions = func(shop string) []Option {
+		if shop != "fooshop.myshopify.com" {
+			t.Errorf("ShopOptions called with %s", shop)
+		}
+		return []Option{WithRateLimiter(limiter)}
+	}
+
+	c, err := manager.Client(context.Background(), "fooshop")
+	if err != nil {
+		t.Fatalf("ClientManager.Client returned error: %v", err)
+	}
+	if c.rateLimiter != limiter {
+		t.Error("ShopOptions were not applied")
+	}
+}
+
+func TestClientManagerEvictIdle(t *testing.T) {
+	setup()
+	defer teardown()
+
+	store := NewMemoryTokenStore()
+	_ = store.SetToken(context.Background(), "fooshop", "abcd")
+	_ = store.SetToken(context.Background(), "barshop", "efgh")
+
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	manager := NewClientManager(app, store)
+	manager.IdleTimeout = time.Minute
+	manager.now = clock.Now
+
+	foo, _ := manager.Client(context.Background(), "fooshop")
+	clock.Advance(40 * time.Second)
+	_, _ = manager.Client(context.Background(), "barshop")
+	clock.Advance(40 * time.Second)
+
+	if evicted := manager.EvictIdle(); evicted != 1 {
+		t.Errorf("EvictIdle evicted %d clients, expected 1", evicted)
+	}
+	if _, ok := manager.clients["barshop.myshopify.com"]; !ok {
+		t.Error("barshop client should not have been evicted")
+	}
+
+	// idle clients are evicted while clients are requested too
+	clock.Advance(2 * time.Minute)
+	again, _ := manager.Client(context.Background(), "fooshop")
+	if again == foo {
+		t.Error("fooshop client should have been recreated")
+	}
+	if _, ok := manager.clients["barshop.myshopify.com"]; ok {
+		t.Error("barshop client should have been evicted")
+	}
+}
+
+func TestClientManagerUnauthorized(t *testing.T) {
+	setup()
+	defer teardown()
+
+	store := NewMemoryTokenStore()
+	_ = store.SetToken(context.Background(), "fooshop", "revoked")
+
+	var unauthorized []string
+	manager := NewClientManager(app, store, WithVersion(testApiVersion), skipApiVersionCheck(), WithHTTPClient(client.Client))
+	manager.OnUnauthorized = func(shop string) {
+		unauthorized = append(unauthorized, shop)
+	}
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shop.json", client.pathPrefix),
+		httpmock.NewStringResponder(http.StatusUnauthorized, `{"errors":"[API] Invalid API key or access token"}`))
+
+	c, err := manager.Client(context.Background(), "fooshop")
+	if err != nil {
+		t.Fatalf("ClientManager.Client returned error: %v", err)
+	}
+
+	if _, err := c.Shop.Get(context.Background(), nil); err == nil {
+		t.Fatal("Shop.Get should return error")
+	}
+
+	if len(unauthorized) != 1 || unauthorized[0] != "fooshop.myshopify.com" {
+		t.Errorf("OnUnauthorized called with %v", unauthorized)
+	}
+	if _, ok := manager.clients["fooshop.myshopify.com"]; ok {
+		t.Error("client should have been invalidated")
+	}
+
+	// a stale client doesn't invalidate the one created with the new token
+	_ = store.SetToken(context.Background(), "fooshop", "reinstalled")
+	fresh, err := manager.Client(context.Background(), "fooshop")
+	if err != nil {
+		t.Fatalf("ClientManager.Client returned error: %v", err)
+	}
+	if _, err := c.Shop.Get(context.Background(), nil); err == nil {
+		t.Fatal("Shop.Get should return error")
+	}
+	if managed, ok := manager.clients["fooshop.myshopify.com"]; !ok || managed.client != fresh {
+		t.Error("the stale client invalidated the new client")
+	}
+}
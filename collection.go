@@ -15,6 +15,7 @@ type CollectionService interface {
 	Get(ctx context.Context, collectionId uint64, options interface{}) (*Collection, error)
 	ListProducts(ctx context.Context, collectionId uint64, options interface{}) ([]Product, error)
 	ListProductsWithPagination(ctx context.Context, collectionId uint64, options interface{}) ([]Product, *Pagination, error)
+	IterateProducts(collectionId uint64, options interface{}) *Iterator[Product]
 }
 
 // CollectionServiceOp handles communication with the collection related methods of
@@ -71,3 +72,10 @@ func (s *CollectionServiceOp) ListProductsWithPagination(ctx context.Context, co
 
 	return resource.Products, pagination, nil
 }
+
+// IterateProducts returns an Iterator over every product of a collection, requesting pages as needed.
+func (s *CollectionServiceOp) IterateProducts(collectionId uint64, options interface{}) *Iterator[Product] {
+	return NewIterator(func(ctx context.Context, options interface{}) ([]Product, *Pagination, error) {
+		return s.ListProductsWithPagination(ctx, collectionId, options)
+	}, options)
+}
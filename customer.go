@@ -2,6 +2,7 @@ package goshopify
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -19,6 +20,8 @@ const (
 type CustomerService interface {
 	List(context.Context, interface{}) ([]Customer, error)
 	ListWithPagination(ctx context.Context, options interface{}) ([]Customer, *Pagination, error)
+	Iterate(options interface{}) *Iterator[Customer]
+	StreamWithPagination(ctx context.Context, options interface{}, fn func(Customer) error) (*Pagination, error)
 	Count(context.Context, interface{}) (int, error)
 	Get(context.Context, uint64, interface{}) (*Customer, error)
 	Search(context.Context, interface{}) ([]Customer, error)
@@ -124,6 +127,26 @@ func (s *CustomerServiceOp) ListWithPagination(ctx context.Context, options inte
 	return resource.Customers, pagination, nil
 }
 
+// Iterate returns an Iterator over every customer, requesting pages as needed.
+func (s *CustomerServiceOp) Iterate(options interface{}) *Iterator[Customer] {
+	return NewIterator(s.ListWithPagination, options)
+}
+
+// StreamWithPagination calls fn with every customer of a page while the response
+// is read, instead of decoding the whole page at once, and returns pagination
+// to retrieve next/previous results. An error returned by fn stops the stream
+// and is returned.
+func (s *CustomerServiceOp) StreamWithPagination(ctx context.Context, options interface{}, fn func(Customer) error) (*Pagination, error) {
+	path := fmt.Sprintf("%s.json", customersBasePath)
+	return s.client.StreamWithPagination(ctx, path, customersResourceName, options, func(decoder *json.Decoder) error {
+		var customer Customer
+		if err := decoder.Decode(&customer); err != nil {
+			return err
+		}
+		return fn(customer)
+	})
+}
+
 // Count customers
 func (s *CustomerServiceOp) Count(ctx context.Context, options interface{}) (int, error) {
 	path := fmt.Sprintf("%s/count.json", customersBasePath)
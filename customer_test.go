@@ -32,6 +32,45 @@ func TestCustomerList(t *testing.T) {
 	}
 }
 
+func TestCustomerStreamWithPagination(t *testing.T) {
+	setup()
+	defer teardown()
+
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/customers.json", client.pathPrefix)
+	nextURL := fmt.Sprintf("<%s?page_info=pg2&limit=2>; rel=\"next\"", listURL)
+
+	httpmock.RegisterResponder("GET", listURL,
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, `{"customers": [{"id":1},{"id":2}]}`)
+			resp.Header.Set("Link", nextURL)
+			return resp, nil
+		})
+
+	var ids []uint64
+	pagination, err := client.Customer.StreamWithPagination(context.Background(), nil, func(item Customer) error {
+		ids = append(ids, item.Id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Customer.StreamWithPagination returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(ids, []uint64{1, 2}) {
+		t.Errorf("Customer.StreamWithPagination streamed %v, expected [1 2]", ids)
+	}
+
+	expectedPagination := &Pagination{NextPageOptions: &ListOptions{PageInfo: "pg2", Limit: 2}}
+	if !reflect.DeepEqual(pagination, expectedPagination) {
+		t.Errorf("Customer.StreamWithPagination returned pagination %#v, expected %#v", pagination, expectedPagination)
+	}
+
+	// errors of the callback stop the stream
+	stop := errors.New("stop")
+	if _, err := client.Customer.StreamWithPagination(context.Background(), nil, func(Customer) error { return stop }); err != stop {
+		t.Errorf("Customer.StreamWithPagination returned %v, expected %v", err, stop)
+	}
+}
+
 func TestCustomerListWithPagination(t *testing.T) {
 	setup()
 	defer teardown()
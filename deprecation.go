@@ -0,0 +1,104 @@
+package goshopify
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DeprecationNotice describes a deprecated endpoint called by the client, as
+// reported by Shopify's X-Shopify-API-Deprecated-Reason response header.
+type DeprecationNotice struct {
+	// Endpoint identifies the endpoint independently of ids and the api
+	// version, e.g. "GET /orders/:id.json".
+	Endpoint string
+
+	// Path of the most recent deprecated request to the endpoint.
+	Path string
+
+	// Reason is the value of the X-Shopify-API-Deprecated-Reason header,
+	// usually a link to the changelog.
+	Reason string
+
+	// ApiVersion is the version that served the most recent request.
+	ApiVersion string
+
+	// Count is how many responses of the endpoint were marked as deprecated.
+	Count int
+
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// Deprecations returns the deprecated endpoints called by the client, sorted by
+// endpoint. A test suite can fail when it is not empty to catch calls to
+// deprecated endpoints before upgrading the api version.
+func (c *Client) Deprecations() []DeprecationNotice {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	notices := make([]DeprecationNotice, 0, len(c.deprecations))
+	for _, notice := range c.deprecations {
+		notices = append(notices, *notice)
+	}
+	sort.Slice(notices, func(i, j int) bool {
+		return notices[i].Endpoint < notices[j].Endpoint
+	})
+	return notices
+}
+
+// recordDeprecation collects the deprecation notice of a response. The first
+// notice of an endpoint is logged as a warning and passed to the handler set
+// with WithDeprecationHandler.
+func (c *Client) recordDeprecation(req *http.Request, resp *http.Response) {
+	reason := resp.Header.Get("X-Shopify-API-Deprecated-Reason")
+	if reason == "" {
+		return
+	}
+
+	endpoint := deprecationEndpoint(req.Method, req.URL.Path)
+	now := time.Now()
+
+	c.mu.Lock()
+	if c.deprecations == nil {
+		c.deprecations = make(map[string]*DeprecationNotice)
+	}
+	notice, seen := c.deprecations[endpoint]
+	if !seen {
+		notice = &DeprecationNotice{Endpoint: endpoint, FirstSeen: now}
+		c.deprecations[endpoint] = notice
+	}
+	notice.Path = req.URL.Path
+	notice.Reason = reason
+	notice.ApiVersion = resp.Header.Get("X-Shopify-API-Version")
+	notice.Count++
+	notice.LastSeen = now
+	copied := *notice
+	c.mu.Unlock()
+
+	if seen {
+		return
+	}
+
+	c.log.Warnf("deprecated endpoint %s called: %s", endpoint, reason)
+	if c.onDeprecation != nil {
+		c.onDeprecation(copied)
+	}
+}
+
+// deprecationEndpoint names the endpoint of a request by its method and path,
+// without the api prefix and with ids replaced by ":id".
+func deprecationEndpoint(method, path string) string {
+	path = resourcePathPrefixRegex.ReplaceAllString(path, "")
+
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		id := strings.TrimSuffix(part, ".json")
+		if isResourceId(id) {
+			parts[i] = ":id" + strings.TrimPrefix(part, id)
+		}
+	}
+
+	return method + " /" + strings.Join(parts, "/")
+}
@@ -0,0 +1,78 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestDeprecationEndpoint(t *testing.T) {
+	cases := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{"GET", "/admin/api/2024-01/orders.json", "GET /orders.json"},
+		{"GET", "/admin/api/2024-01/orders/1.json", "GET /orders/:id.json"},
+		{"PUT", "/admin/products/1/images/2.json", "PUT /products/:id/images/:id.json"},
+		{"POST", "/admin/api/unstable/graphql.json", "POST /graphql.json"},
+	}
+
+	for _, c := range cases {
+		if actual := deprecationEndpoint(c.method, c.path); actual != c.expected {
+			t.Errorf("deprecationEndpoint(%s, %s) = %s, expected %s", c.method, c.path, actual, c.expected)
+		}
+	}
+}
+
+func TestClientDeprecations(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var notified []DeprecationNotice
+	WithDeprecationHandler(func(notice DeprecationNotice) {
+		notified = append(notified, notice)
+	})(client)
+
+	reason := "https://shopify.dev/changelog/orders-deprecated"
+	for _, id := range []string{"1", "2"} {
+		httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders/%s.json", client.pathPrefix, id),
+			func(req *http.Request) (*http.Response, error) {
+				resp := httpmock.NewStringResponse(200, `{"order":{"id":1}}`)
+				resp.Header.Set("X-Shopify-API-Deprecated-Reason", reason)
+				resp.Header.Set("X-Shopify-API-Version", testApiVersion)
+				return resp, nil
+			})
+	}
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders/count.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"count": 7}`))
+
+	for _, id := range []uint64{1, 2} {
+		if _, err := client.Order.Get(context.Background(), id, nil); err != nil {
+			t.Fatalf("Order.Get returned error: %v", err)
+		}
+	}
+	if _, err := client.Order.Count(context.Background(), nil); err != nil {
+		t.Fatalf("Order.Count returned error: %v", err)
+	}
+
+	deprecations := client.Deprecations()
+	if len(deprecations) != 1 {
+		t.Fatalf("Client.Deprecations returned %d notices, expected 1", len(deprecations))
+	}
+
+	notice := deprecations[0]
+	if notice.Endpoint != "GET /orders/:id.json" || notice.Reason != reason || notice.Count != 2 ||
+		notice.ApiVersion != testApiVersion || notice.Path != fmt.Sprintf("/%s/orders/2.json", client.pathPrefix) ||
+		notice.FirstSeen.IsZero() || notice.LastSeen.Before(notice.FirstSeen) {
+		t.Errorf("unexpected deprecation notice %#v", notice)
+	}
+
+	// the handler is only called for the first notice of an endpoint
+	if len(notified) != 1 || notified[0].Count != 1 {
+		t.Errorf("deprecation handler called with %#v", notified)
+	}
+}
@@ -18,6 +18,7 @@ const (
 // See: https://help.shopify.com/api/reference/orders/draftorder
 type DraftOrderService interface {
 	List(context.Context, interface{}) ([]DraftOrder, error)
+	ListWithPagination(context.Context, interface{}) ([]DraftOrder, *Pagination, error)
 	Count(context.Context, interface{}) (int, error)
 	Get(context.Context, uint64, interface{}) (*DraftOrder, error)
 	Create(context.Context, DraftOrder) (*DraftOrder, error)
@@ -136,6 +137,19 @@ func (s *DraftOrderServiceOp) List(ctx context.Context, options interface{}) ([]
 	return resource.DraftOrders, err
 }
 
+// ListWithPagination lists draft orders and return pagination to retrieve next/previous results.
+func (s *DraftOrderServiceOp) ListWithPagination(ctx context.Context, options interface{}) ([]DraftOrder, *Pagination, error) {
+	path := fmt.Sprintf("%s.json", draftOrdersBasePath)
+	resource := new(DraftOrdersResource)
+
+	pagination, err := s.client.ListWithPagination(ctx, path, resource, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource.DraftOrders, pagination, nil
+}
+
 // Count draft orders
 func (s *DraftOrderServiceOp) Count(ctx context.Context, options interface{}) (int, error) {
 	path := fmt.Sprintf("%s/count.json", draftOrdersBasePath)
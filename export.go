@@ -0,0 +1,237 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrCheckpointNotFound is returned by a CheckpointStore when it has no
+// checkpoint for an export.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// Checkpoint is the progress of an export, saved after every page.
+type Checkpoint struct {
+	// Cursor is the page_info of the next page to export.
+	Cursor string `json:"cursor,omitempty"`
+
+	// Limit is the page size the Cursor was created with.
+	Limit int `json:"limit,omitempty"`
+
+	// SinceId is the watermark of the export: the highest id exported so far.
+	SinceId uint64 `json:"since_id,omitempty"`
+}
+
+// CheckpointStore stores the checkpoints of exports by name. Implementations
+// must be safe for concurrent use.
+type CheckpointStore interface {
+	// LoadCheckpoint returns the export's checkpoint or ErrCheckpointNotFound.
+	LoadCheckpoint(ctx context.Context, name string) (Checkpoint, error)
+	SaveCheckpoint(ctx context.Context, name string, checkpoint Checkpoint) error
+	DeleteCheckpoint(ctx context.Context, name string) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore keeping checkpoints in memory.
+type MemoryCheckpointStore struct {
+	checkpointStore
+}
+
+// NewMemoryCheckpointStore returns an empty MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpointStore{newMemoryValueStore[Checkpoint](ErrCheckpointNotFound)}}
+}
+
+// FileCheckpointStore is a CheckpointStore keeping checkpoints in a JSON file.
+type FileCheckpointStore struct {
+	checkpointStore
+}
+
+// NewFileCheckpointStore returns a FileCheckpointStore using the file at path,
+// which is created on the first SaveCheckpoint.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{checkpointStore{newFileValueStore[Checkpoint](path, ErrCheckpointNotFound)}}
+}
+
+// checkpointStore implements CheckpointStore.
+type checkpointStore struct {
+	checkpoints *valueStore[Checkpoint]
+}
+
+// LoadCheckpoint implements CheckpointStore.
+func (s checkpointStore) LoadCheckpoint(_ context.Context, name string) (Checkpoint, error) {
+	return s.checkpoints.load(name)
+}
+
+// SaveCheckpoint implements CheckpointStore.
+func (s checkpointStore) SaveCheckpoint(_ context.Context, name string, checkpoint Checkpoint) error {
+	return s.checkpoints.save(name, checkpoint)
+}
+
+// DeleteCheckpoint implements CheckpointStore.
+func (s checkpointStore) DeleteCheckpoint(_ context.Context, name string) error {
+	return s.checkpoints.delete(name)
+}
+
+const defaultExportPageSize = 250
+
+// ExportRunner exports every resource of a list endpoint, saving a Checkpoint
+// after each page so that an interrupted export resumes where it stopped:
+//
+//	runner := goshopify.NewExportRunner("products", store, client.Product.ListWithPagination)
+//	err := runner.Run(ctx, func(product goshopify.Product) error {
+//		return write(product)
+//	})
+//
+// Run continues from the saved page_info cursor. When Shopify rejects the
+// cursor, e.g. because it expired, the export restarts from the watermark
+// instead, with the options returned by Options. Every page is listed with a
+// since_id, 0 for a new export, so that resources come in id order and no
+// resource below the watermark is left to export.
+//
+// Resources of the page being exported when the export was interrupted are
+// exported again on resume. The checkpoint is deleted once every page was
+// exported, so the next Run starts a new export.
+type ExportRunner[T any] struct {
+	// Name identifies the export in the CheckpointStore.
+	Name string
+
+	Store CheckpointStore
+	List  ListFunc[T]
+
+	// Options returns the options of a new export, with an empty checkpoint,
+	// or of an export restarted from the checkpoint's watermark. The options
+	// must set SinceId to the checkpoint's SinceId, even when it is 0, or
+	// resources are skipped when the export restarts. Defaults to a
+	// ListOptions of 250 resources with the checkpoint's SinceId.
+	Options func(checkpoint Checkpoint) interface{}
+
+	// Watermark returns the id of a resource. Defaults to the resource's Id
+	// field.
+	Watermark func(resource T) uint64
+}
+
+// NewExportRunner returns an ExportRunner of the resources listed by list.
+func NewExportRunner[T any](name string, store CheckpointStore, list ListFunc[T]) *ExportRunner[T] {
+	return &ExportRunner[T]{Name: name, Store: store, List: list}
+}
+
+// Run calls fn with every resource, starting from the saved checkpoint. An
+// error returned by fn stops the export, which resumes with the same page.
+func (r *ExportRunner[T]) Run(ctx context.Context, fn func(T) error) error {
+	checkpoint, err := r.Store.LoadCheckpoint(ctx, r.Name)
+	if err != nil && !errors.Is(err, ErrCheckpointNotFound) {
+		return err
+	}
+
+	var options interface{}
+	if checkpoint.Cursor != "" {
+		options = &ListOptions{PageInfo: checkpoint.Cursor, Limit: checkpoint.Limit}
+	} else {
+		options = r.watermarkOptions(checkpoint)
+	}
+
+	for {
+		resources, pagination, err := r.List(ctx, options)
+		if err != nil && checkpoint.Cursor != "" && isInvalidCursorError(err) {
+			// restart from the watermark
+			checkpoint.Cursor = ""
+			options = r.watermarkOptions(checkpoint)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, resource := range resources {
+			if err := fn(resource); err != nil {
+				return err
+			}
+			r.advanceWatermark(&checkpoint, resource)
+		}
+
+		if pagination == nil || pagination.NextPageOptions == nil {
+			return r.Store.DeleteCheckpoint(ctx, r.Name)
+		}
+
+		next := pagination.NextPageOptions
+		checkpoint.Cursor = next.PageInfo
+		checkpoint.Limit = next.Limit
+		if err := r.Store.SaveCheckpoint(ctx, r.Name, checkpoint); err != nil {
+			return err
+		}
+		options = &ListOptions{PageInfo: next.PageInfo, Limit: next.Limit}
+	}
+}
+
+func (r *ExportRunner[T]) watermarkOptions(checkpoint Checkpoint) interface{} {
+	if r.Options != nil {
+		return r.Options(checkpoint)
+	}
+
+	sinceId := checkpoint.SinceId
+	return &ListOptions{SinceId: &sinceId, Limit: defaultExportPageSize}
+}
+
+func (r *ExportRunner[T]) advanceWatermark(checkpoint *Checkpoint, resource T) {
+	var id uint64
+	if r.Watermark != nil {
+		id = r.Watermark(resource)
+	} else {
+		id, _ = resourceWatermark(resource)
+	}
+
+	if id > checkpoint.SinceId {
+		checkpoint.SinceId = id
+	}
+}
+
+// resourceWatermark reads the Id and UpdatedAt fields of a resource.
+func resourceWatermark(resource interface{}) (uint64, time.Time) {
+	v := reflect.Indirect(reflect.ValueOf(resource))
+	if v.Kind() != reflect.Struct {
+		return 0, time.Time{}
+	}
+
+	var id uint64
+	if field := v.FieldByName("Id"); field.IsValid() && field.Kind() == reflect.Uint64 {
+		id = field.Uint()
+	}
+	return id, resourceTime(v, "UpdatedAt")
+}
+
+// resourceTime reads a time.Time or *time.Time field of a resource struct.
+func resourceTime(v reflect.Value, name string) time.Time {
+	field := v.FieldByName(name)
+	if !field.IsValid() {
+		return time.Time{}
+	}
+
+	switch value := field.Interface().(type) {
+	case time.Time:
+		return value
+	case *time.Time:
+		if value != nil {
+			return *value
+		}
+	}
+	return time.Time{}
+}
+
+// isInvalidCursorError reports whether Shopify rejected a page_info, which
+// happens once it expired.
+func isInvalidCursorError(err error) bool {
+	var respErr ResponseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+	if respErr.Status != http.StatusBadRequest && respErr.Status != http.StatusUnprocessableEntity {
+		return false
+	}
+	if _, ok := respErr.FieldErrors["page_info"]; ok {
+		return true
+	}
+	return strings.Contains(respErr.Error(), "page_info")
+}
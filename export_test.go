@@ -0,0 +1,197 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// registerExportPages serves two pages of products, the first one being
+// requested with since_id=0 and the second one with page_info=pg2
+func registerExportPages() {
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/products.json", client.pathPrefix)
+
+	httpmock.RegisterResponderWithQuery("GET", listURL, "since_id=0&limit=250",
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, `{"products": [{"id":1,"updated_at":"2024-01-02T00:00:00Z"},{"id":2,"updated_at":"2024-01-01T00:00:00Z"}]}`)
+			resp.Header.Set("Link", fmt.Sprintf(`<%s?page_info=pg2&limit=250>; rel="next"`, listURL))
+			return resp, nil
+		})
+	httpmock.RegisterResponderWithQuery("GET", listURL, "page_info=pg2&limit=250",
+		httpmock.NewStringResponder(200, `{"products": [{"id":3,"updated_at":"2024-01-03T00:00:00Z"}]}`))
+}
+
+func TestExportRunnerResumes(t *testing.T) {
+	setup()
+	defer teardown()
+	registerExportPages()
+
+	store := NewMemoryCheckpointStore()
+	runner := NewExportRunner("products", store, client.Product.ListWithPagination)
+
+	// the export fails on the second page
+	var exported []uint64
+	failure := errors.New("disk full")
+	err := runner.Run(context.Background(), func(product Product) error {
+		if product.Id == 3 {
+			return failure
+		}
+		exported = append(exported, product.Id)
+		return nil
+	})
+	if err != failure {
+		t.Fatalf("ExportRunner.Run returned %v, expected %v", err, failure)
+	}
+
+	checkpoint, err := store.LoadCheckpoint(context.Background(), "products")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint returned error: %v", err)
+	}
+	expected := Checkpoint{Cursor: "pg2", Limit: 250, SinceId: 2}
+	if !reflect.DeepEqual(checkpoint, expected) {
+		t.Errorf("checkpoint = %#v, expected %#v", checkpoint, expected)
+	}
+
+	// the next run continues with the second page
+	err = runner.Run(context.Background(), func(product Product) error {
+		exported = append(exported, product.Id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportRunner.Run returned error: %v", err)
+	}
+	if !reflect.DeepEqual(exported, []uint64{1, 2, 3}) {
+		t.Errorf("exported %v, expected [1 2 3]", exported)
+	}
+
+	// completed exports are forgotten
+	if _, err := store.LoadCheckpoint(context.Background(), "products"); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("LoadCheckpoint returned %v, expected ErrCheckpointNotFound", err)
+	}
+}
+
+func TestExportRunnerExpiredCursor(t *testing.T) {
+	setup()
+	defer teardown()
+
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/products.json", client.pathPrefix)
+	httpmock.RegisterResponderWithQuery("GET", listURL, "page_info=expired&limit=250",
+		httpmock.NewStringResponder(400, `{"errors":{"page_info":["Invalid value."]}}`))
+	httpmock.RegisterResponderWithQuery("GET", listURL, "since_id=2&limit=250",
+		httpmock.NewStringResponder(200, `{"products": [{"id":3}]}`))
+
+	store := NewMemoryCheckpointStore()
+	_ = store.SaveCheckpoint(context.Background(), "products", Checkpoint{Cursor: "expired", Limit: 250, SinceId: 2})
+
+	runner := NewExportRunner("products", store, client.Product.ListWithPagination)
+
+	var exported []uint64
+	err := runner.Run(context.Background(), func(product Product) error {
+		exported = append(exported, product.Id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportRunner.Run returned error: %v", err)
+	}
+	if !reflect.DeepEqual(exported, []uint64{3}) {
+		t.Errorf("exported %v, expected [3]", exported)
+	}
+}
+
+func TestExportRunnerExpiredCursorUnorderedPage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// the first page is not in id order, the export restarts after its
+	// highest id once the cursor of the second page is rejected
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/products.json", client.pathPrefix)
+	httpmock.RegisterResponderWithQuery("GET", listURL, "since_id=0&limit=250",
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, `{"products": [{"id":5},{"id":2}]}`)
+			resp.Header.Set("Link", fmt.Sprintf(`<%s?page_info=pg2&limit=250>; rel="next"`, listURL))
+			return resp, nil
+		})
+	httpmock.RegisterResponderWithQuery("GET", listURL, "page_info=pg2&limit=250",
+		httpmock.NewStringResponder(400, `{"errors":{"page_info":["Invalid value."]}}`))
+	httpmock.RegisterResponderWithQuery("GET", listURL, "since_id=5&limit=250",
+		httpmock.NewStringResponder(200, `{"products": [{"id":6}]}`))
+
+	runner := NewExportRunner("products", NewMemoryCheckpointStore(), client.Product.ListWithPagination)
+
+	var exported []uint64
+	err := runner.Run(context.Background(), func(product Product) error {
+		exported = append(exported, product.Id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportRunner.Run returned error: %v", err)
+	}
+	if !reflect.DeepEqual(exported, []uint64{5, 2, 6}) {
+		t.Errorf("exported %v, expected [5 2 6]", exported)
+	}
+}
+
+func TestExportRunnerOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/orders.json", client.pathPrefix)
+	httpmock.RegisterResponderWithQuery("GET", listURL, "since_id=0&status=any&limit=50",
+		httpmock.NewStringResponder(200, `{"orders": [{"id":1}]}`))
+
+	runner := NewExportRunner("orders", NewMemoryCheckpointStore(), client.Order.ListWithPagination)
+	runner.Options = func(checkpoint Checkpoint) interface{} {
+		return &OrderListOptions{Status: OrderStatusAny, ListOptions: ListOptions{SinceId: &checkpoint.SinceId, Limit: 50}}
+	}
+
+	count := 0
+	if err := runner.Run(context.Background(), func(Order) error { count++; return nil }); err != nil {
+		t.Fatalf("ExportRunner.Run returned error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("exported %d orders, expected 1", count)
+	}
+}
+
+func TestIsInvalidCursorError(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{ResponseError{Status: 400, FieldErrors: map[string][]string{"page_info": {"Invalid value."}}}, true},
+		{ResponseError{Status: 400, Message: "Invalid page_info"}, true},
+		{ResponseError{Status: 400, Message: "bad request"}, false},
+		{ResponseError{Status: 500, Message: "page_info"}, false},
+		{errors.New("page_info"), false},
+	}
+
+	for _, c := range cases {
+		if actual := isInvalidCursorError(c.err); actual != c.expected {
+			t.Errorf("isInvalidCursorError(%v) = %v, expected %v", c.err, actual, c.expected)
+		}
+	}
+}
+
+func TestResourceWatermark(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	id, at := resourceWatermark(Product{Id: 1, UpdatedAt: &updatedAt})
+	if id != 1 || !at.Equal(updatedAt) {
+		t.Errorf("resourceWatermark(Product) = %d, %v", id, at)
+	}
+
+	id, at = resourceWatermark(&Order{Id: 2})
+	if id != 2 || !at.IsZero() {
+		t.Errorf("resourceWatermark(*Order) = %d, %v", id, at)
+	}
+
+	if id, _ := resourceWatermark(3); id != 0 {
+		t.Errorf("resourceWatermark(int) = %d, expected 0", id)
+	}
+}
@@ -0,0 +1,298 @@
+package goshopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const gidPrefix = "gid://shopify/"
+
+// GID is the global id of a resource in the GraphQL Admin API, e.g.
+// gid://shopify/Product/123 for the product of REST id 123. REST resources
+// hold theirs in AdminGraphqlApiId, parsed by their GID method.
+// See: https://shopify.dev/docs/api/usage/gids
+type GID struct {
+	// ResourceType is the GraphQL type of the resource, e.g. "ProductVariant".
+	ResourceType string
+
+	// Id is the REST id of the resource.
+	Id uint64
+
+	// RawQuery is the encoded query of ids scoped to another resource,
+	// without the '?', e.g. "inventory_item_id=1" for inventory levels.
+	RawQuery string
+}
+
+// ParseGID parses a global id of the form gid://shopify/<type>/<id>.
+func ParseGID(s string) (*GID, error) {
+	path := strings.TrimPrefix(s, gidPrefix)
+	if path == s {
+		return nil, fmt.Errorf("invalid gid %q, expected gid://shopify/<type>/<id>", s)
+	}
+
+	gid := &GID{}
+	path, gid.RawQuery, _ = strings.Cut(path, "?")
+	resourceType, id, _ := strings.Cut(path, "/")
+	if resourceType == "" || !isResourceId(id) {
+		return nil, fmt.Errorf("invalid gid %q, expected gid://shopify/<type>/<id>", s)
+	}
+
+	var err error
+	if gid.Id, err = strconv.ParseUint(id, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid gid %q: %w", s, err)
+	}
+	gid.ResourceType = resourceType
+	return gid, nil
+}
+
+// String formats the global id, e.g. gid://shopify/Product/123, or returns an
+// empty string for the zero GID.
+func (g GID) String() string {
+	if g == (GID{}) {
+		return ""
+	}
+
+	s := fmt.Sprintf("%s%s/%d", gidPrefix, g.ResourceType, g.Id)
+	if g.RawQuery != "" {
+		s += "?" + g.RawQuery
+	}
+	return s
+}
+
+// GraphQLType declares global ids as GraphQL ID variables.
+func (g GID) GraphQLType() string {
+	return "ID!"
+}
+
+// MarshalJSON encodes the global id as a string, or null for the zero GID.
+func (g GID) MarshalJSON() ([]byte, error) {
+	if g == (GID{}) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(g.String())
+}
+
+// UnmarshalJSON decodes a global id string, an empty string decodes to the
+// zero GID.
+func (g *GID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*g = GID{}
+		return nil
+	}
+
+	gid, err := ParseGID(s)
+	if err != nil {
+		return err
+	}
+	*g = *gid
+	return nil
+}
+
+// adminGraphqlApiGID parses the AdminGraphqlApiId of a REST resource
+func adminGraphqlApiGID(adminGraphqlApiId string) (*GID, error) {
+	if adminGraphqlApiId == "" {
+		return nil, fmt.Errorf("admin_graphql_api_id is not set")
+	}
+	return ParseGID(adminGraphqlApiId)
+}
+
+// GID parses the AdminGraphqlApiId of an abandoned checkout.
+func (a AbandonedCheckout) GID() (*GID, error) { return adminGraphqlApiGID(a.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of a blog.
+func (b Blog) GID() (*GID, error) { return adminGraphqlApiGID(b.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of a carrier service.
+func (c CarrierService) GID() (*GID, error) { return adminGraphqlApiGID(c.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of a fulfillment service.
+func (f FulfillmentServiceData) GID() (*GID, error) { return adminGraphqlApiGID(f.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of an image.
+func (i Image) GID() (*GID, error) { return adminGraphqlApiGID(i.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of an inventory item.
+func (i InventoryItem) GID() (*GID, error) { return adminGraphqlApiGID(i.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of an inventory level.
+func (i InventoryLevel) GID() (*GID, error) { return adminGraphqlApiGID(i.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of a location.
+func (l Location) GID() (*GID, error) { return adminGraphqlApiGID(l.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of a metafield.
+func (m Metafield) GID() (*GID, error) { return adminGraphqlApiGID(m.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of a product.
+func (p Product) GID() (*GID, error) { return adminGraphqlApiGID(p.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of a shipping zone.
+func (s ShippingZone) GID() (*GID, error) { return adminGraphqlApiGID(s.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of a storefront access token.
+func (s StorefrontAccessToken) GID() (*GID, error) { return adminGraphqlApiGID(s.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of a theme.
+func (t Theme) GID() (*GID, error) { return adminGraphqlApiGID(t.AdminGraphqlApiId) }
+
+// GID parses the AdminGraphqlApiId of a variant.
+func (v Variant) GID() (*GID, error) { return adminGraphqlApiGID(v.AdminGraphqlApiId) }
+
+func newGID(resourceType string, id uint64) *GID {
+	return &GID{ResourceType: resourceType, Id: id}
+}
+
+// NewAbandonedCheckoutGID returns the global id of an AbandonedCheckout.
+func NewAbandonedCheckoutGID(id uint64) *GID { return newGID("AbandonedCheckout", id) }
+
+// NewApplicationChargeGID returns the global id of an ApplicationCharge, an
+// AppPurchaseOneTime in GraphQL.
+func NewApplicationChargeGID(id uint64) *GID { return newGID("AppPurchaseOneTime", id) }
+
+// NewBlogGID returns the global id of a Blog.
+func NewBlogGID(id uint64) *GID { return newGID("Blog", id) }
+
+// NewCarrierServiceGID returns the global id of a CarrierService, a
+// DeliveryCarrierService in GraphQL.
+func NewCarrierServiceGID(id uint64) *GID { return newGID("DeliveryCarrierService", id) }
+
+// NewCollectionGID returns the global id of a Collection, CustomCollection or
+// SmartCollection.
+func NewCollectionGID(id uint64) *GID { return newGID("Collection", id) }
+
+// NewCustomerGID returns the global id of a Customer.
+func NewCustomerGID(id uint64) *GID { return newGID("Customer", id) }
+
+// NewCustomerAddressGID returns the global id of a CustomerAddress, a
+// MailingAddress in GraphQL.
+func NewCustomerAddressGID(id uint64) *GID {
+	return &GID{ResourceType: "MailingAddress", Id: id, RawQuery: "model_name=CustomerAddress"}
+}
+
+// NewDiscountCodeGID returns the global id of a PriceRuleDiscountCode, a
+// DiscountRedeemCode in GraphQL.
+func NewDiscountCodeGID(id uint64) *GID { return newGID("DiscountRedeemCode", id) }
+
+// NewDraftOrderGID returns the global id of a DraftOrder.
+func NewDraftOrderGID(id uint64) *GID { return newGID("DraftOrder", id) }
+
+// NewDraftOrderLineItemGID returns the global id of a LineItem of a DraftOrder.
+func NewDraftOrderLineItemGID(id uint64) *GID { return newGID("DraftOrderLineItem", id) }
+
+// NewFulfillmentGID returns the global id of a Fulfillment.
+func NewFulfillmentGID(id uint64) *GID { return newGID("Fulfillment", id) }
+
+// NewFulfillmentEventGID returns the global id of a FulfillmentEvent.
+func NewFulfillmentEventGID(id uint64) *GID { return newGID("FulfillmentEvent", id) }
+
+// NewFulfillmentOrderGID returns the global id of a FulfillmentOrder.
+func NewFulfillmentOrderGID(id uint64) *GID { return newGID("FulfillmentOrder", id) }
+
+// NewFulfillmentServiceGID returns the global id of a FulfillmentService as
+// returned by the REST API.
+func NewFulfillmentServiceGID(id uint64) *GID { return newGID("ApiFulfillmentService", id) }
+
+// NewGiftCardGID returns the global id of a GiftCard.
+func NewGiftCardGID(id uint64) *GID { return newGID("GiftCard", id) }
+
+// NewImageGID returns the global id of an Image of a product, a ProductImage
+// in GraphQL.
+func NewImageGID(id uint64) *GID { return newGID("ProductImage", id) }
+
+// NewInventoryItemGID returns the global id of an InventoryItem.
+func NewInventoryItemGID(id uint64) *GID { return newGID("InventoryItem", id) }
+
+// NewInventoryLevelGID returns the global id of the InventoryLevel of an
+// inventory item at a location.
+func NewInventoryLevelGID(locationId, inventoryItemId uint64) *GID {
+	return &GID{
+		ResourceType: "InventoryLevel",
+		Id:           locationId,
+		RawQuery:     fmt.Sprintf("inventory_item_id=%d", inventoryItemId),
+	}
+}
+
+// NewLineItemGID returns the global id of a LineItem of an order.
+func NewLineItemGID(id uint64) *GID { return newGID("LineItem", id) }
+
+// NewLocationGID returns the global id of a Location.
+func NewLocationGID(id uint64) *GID { return newGID("Location", id) }
+
+// NewMetafieldGID returns the global id of a Metafield.
+func NewMetafieldGID(id uint64) *GID { return newGID("Metafield", id) }
+
+// NewOrderGID returns the global id of an Order.
+func NewOrderGID(id uint64) *GID { return newGID("Order", id) }
+
+// NewOrderRiskGID returns the global id of an OrderRisk.
+func NewOrderRiskGID(id uint64) *GID { return newGID("OrderRisk", id) }
+
+// NewPageGID returns the global id of a Page.
+func NewPageGID(id uint64) *GID { return newGID("Page", id) }
+
+// NewPaymentsTransactionGID returns the global id of a PaymentsTransactions,
+// a ShopifyPaymentsBalanceTransaction in GraphQL.
+func NewPaymentsTransactionGID(id uint64) *GID {
+	return newGID("ShopifyPaymentsBalanceTransaction", id)
+}
+
+// NewPayoutGID returns the global id of a Payout, a ShopifyPaymentsPayout in
+// GraphQL.
+func NewPayoutGID(id uint64) *GID { return newGID("ShopifyPaymentsPayout", id) }
+
+// NewPriceRuleGID returns the global id of a PriceRule.
+func NewPriceRuleGID(id uint64) *GID { return newGID("PriceRule", id) }
+
+// NewProductGID returns the global id of a Product or ProductListing.
+func NewProductGID(id uint64) *GID { return newGID("Product", id) }
+
+// NewRecurringApplicationChargeGID returns the global id of a
+// RecurringApplicationCharge, an AppSubscription in GraphQL.
+func NewRecurringApplicationChargeGID(id uint64) *GID { return newGID("AppSubscription", id) }
+
+// NewRedirectGID returns the global id of a Redirect, a UrlRedirect in GraphQL.
+func NewRedirectGID(id uint64) *GID { return newGID("UrlRedirect", id) }
+
+// NewScriptTagGID returns the global id of a ScriptTag.
+func NewScriptTagGID(id uint64) *GID { return newGID("ScriptTag", id) }
+
+// NewShippingZoneGID returns the global id of a ShippingZone, a DeliveryZone
+// in GraphQL.
+func NewShippingZoneGID(id uint64) *GID { return newGID("DeliveryZone", id) }
+
+// NewShopGID returns the global id of a Shop.
+func NewShopGID(id uint64) *GID { return newGID("Shop", id) }
+
+// NewStorefrontAccessTokenGID returns the global id of a
+// StorefrontAccessToken.
+func NewStorefrontAccessTokenGID(id uint64) *GID { return newGID("StorefrontAccessToken", id) }
+
+// NewThemeGID returns the global id of a Theme, an OnlineStoreTheme in
+// GraphQL.
+func NewThemeGID(id uint64) *GID { return newGID("OnlineStoreTheme", id) }
+
+// NewTransactionGID returns the global id of a Transaction, an
+// OrderTransaction in GraphQL.
+func NewTransactionGID(id uint64) *GID { return newGID("OrderTransaction", id) }
+
+// NewUsageChargeGID returns the global id of a UsageCharge, an AppUsageRecord
+// in GraphQL.
+func NewUsageChargeGID(id uint64) *GID { return newGID("AppUsageRecord", id) }
+
+// NewVariantGID returns the global id of a Variant, a ProductVariant in
+// GraphQL.
+func NewVariantGID(id uint64) *GID { return newGID("ProductVariant", id) }
+
+// NewWebhookGID returns the global id of a Webhook, a WebhookSubscription in
+// GraphQL.
+func NewWebhookGID(id uint64) *GID { return newGID("WebhookSubscription", id) }
@@ -0,0 +1,139 @@
+package goshopify
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseGID(t *testing.T) {
+	cases := map[string]*GID{
+		"gid://shopify/Product/123":                                          NewProductGID(123),
+		"gid://shopify/ProductVariant/18446744073709551615":                  NewVariantGID(18446744073709551615),
+		"gid://shopify/InventoryLevel/905684977?inventory_item_id=808950810": NewInventoryLevelGID(905684977, 808950810),
+		"gid://shopify/MailingAddress/1?model_name=CustomerAddress":          NewCustomerAddressGID(1),
+	}
+
+	for s, expected := range cases {
+		gid, err := ParseGID(s)
+		if err != nil {
+			t.Errorf("ParseGID(%s) returned error: %v", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(gid, expected) {
+			t.Errorf("ParseGID(%s) = %+v, expected %+v", s, gid, expected)
+		}
+		if gid.String() != s {
+			t.Errorf("GID.String() = %s, expected %s", gid.String(), s)
+		}
+	}
+
+	for _, s := range []string{
+		"",
+		"123",
+		"gid://shopify/Product",
+		"gid://shopify/Product/",
+		"gid://shopify//123",
+		"gid://shopify/Cart/c1",
+		"gid://shopify/Product/-1",
+		"gid://shopify/Product/18446744073709551616",
+		"gid://other/Product/123",
+	} {
+		if gid, err := ParseGID(s); err == nil {
+			t.Errorf("ParseGID(%q) = %+v, expected an error", s, gid)
+		}
+	}
+}
+
+func TestGIDJSON(t *testing.T) {
+	v := struct {
+		Product  *GID `json:"product"`
+		Variant  GID  `json:"variant"`
+		Location *GID `json:"location,omitempty"`
+		Empty    GID  `json:"empty"`
+	}{
+		Product: NewProductGID(1),
+		Variant: *NewVariantGID(2),
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	expected := `{"product":"gid://shopify/Product/1","variant":"gid://shopify/ProductVariant/2","empty":null}`
+	if string(data) != expected {
+		t.Errorf("json.Marshal returned %s, expected %s", data, expected)
+	}
+
+	v.Product, v.Variant = nil, GID{}
+	if err := json.Unmarshal([]byte(`{"product":"gid://shopify/Product/1","variant":"gid:\/\/shopify\/ProductVariant\/2","location":null,"empty":""}`), &v); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if !reflect.DeepEqual(v.Product, NewProductGID(1)) || v.Variant != *NewVariantGID(2) || v.Location != nil || v.Empty != (GID{}) {
+		t.Errorf("json.Unmarshal decoded %+v", v)
+	}
+
+	for _, data := range []string{`{"product":"Product/1"}`, `{"product":1}`} {
+		if err := json.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("json.Unmarshal(%s) should return an error", data)
+		}
+	}
+}
+
+func TestGIDAdminGraphqlApiId(t *testing.T) {
+	product := ProductResource{}
+	if err := json.Unmarshal(loadFixture("product.json"), &product); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+
+	if gid, err := product.Product.GID(); err != nil || !reflect.DeepEqual(gid, NewProductGID(product.Product.Id)) {
+		t.Errorf("Product.GID() returned %+v, %v, expected the gid of product %d", gid, err, product.Product.Id)
+	}
+	for _, variant := range product.Product.Variants {
+		if gid, err := variant.GID(); err != nil || !reflect.DeepEqual(gid, NewVariantGID(variant.Id)) {
+			t.Errorf("Variant.GID() returned %+v, %v, expected the gid of variant %d", gid, err, variant.Id)
+		}
+	}
+
+	levels := InventoryLevelsResource{}
+	if err := json.Unmarshal(loadFixture("inventory_levels.json"), &levels); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if gid, err := levels.InventoryLevels[0].GID(); err != nil || gid.RawQuery != "inventory_item_id=808950810" {
+		t.Errorf("InventoryLevel.GID() returned %+v, %v", gid, err)
+	}
+
+	// an unexpected id fails the accessor only, not the decoding of the resource
+	location := LocationResource{}
+	if err := json.Unmarshal([]byte(`{"location":{"id":1,"admin_graphql_api_id":"gid://shopify/Location/unexpected"}}`), &location); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if _, err := location.Location.GID(); err == nil {
+		t.Error("Location.GID() of an unexpected id should return an error")
+	}
+	if _, err := (Metafield{}).GID(); err == nil {
+		t.Error("Metafield.GID() without an id should return an error")
+	}
+}
+
+func TestGIDGraphQLVariable(t *testing.T) {
+	vars := map[string]interface{}{
+		"id":       NewProductGID(1),
+		"ids":      []GID{*NewProductGID(1)},
+		"optional": []*GID{nil},
+	}
+
+	var q struct {
+		Node struct {
+			Id *GID
+		} `graphql:"node(id: $id)"`
+	}
+	query, err := BuildGraphQLQuery(&q, vars)
+	if err != nil {
+		t.Fatalf("BuildGraphQLQuery returned error: %v", err)
+	}
+	expected := `query($id: ID!, $ids: [ID!]!, $optional: [ID]!) { node(id: $id) { id } }`
+	if query != expected {
+		t.Errorf("BuildGraphQLQuery returned\n%s\nexpected\n%s", query, expected)
+	}
+}
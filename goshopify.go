@@ -17,6 +17,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-querystring/query"
@@ -55,6 +56,11 @@ type RateLimitInfo struct {
 }
 
 // Client manages communication with the Shopify API.
+//
+// A Client, and every service attached to it, is safe for concurrent use by
+// multiple goroutines. State that changes between requests (the detected api
+// version, attempt counts and rate limits) is guarded by the client; use
+// GetRateLimits to read a consistent snapshot of the rate limits.
 type Client struct {
 	// HTTP client used to communicate with the Shopify API.
 	Client *http.Client
@@ -74,15 +80,69 @@ type Client struct {
 	// version you're currently using of the api, defaults to "stable"
 	apiVersion string
 
+	// version serving the requests of a client created without a version
+	servedApiVersion string
+
+	// accepts any YYYY-MM apiVersion, such as the 9999-99 version of the tests
+	skipApiVersionCheck bool
+
 	// A permanent access token
 	token string
 
+	// the Storefront API access token of a storefront client, see NewStorefrontClient
+	storefrontToken string
+
 	// max number of retries, defaults to 0 for no retries see WithRetry option
-	retries  int
+	retries int
+
+	// decides which failed requests are retried, see WithRetryPolicy. When nil
+	// a DefaultRetryPolicy of retries attempts is used.
+	retryPolicy RetryPolicy
+
+	// optional limiter throttling REST requests, see WithRateLimiter
+	rateLimiter RateLimiter
+
+	// tracks GraphQL query costs to delay queries before they are throttled
+	graphQLThrottle *graphQLThrottle
+
+	// wraps every request, outermost first, see WithMiddleware
+	middleware []Middleware
+
+	// receives events about every request and GraphQL query, see WithInstrumenter
+	instrumenter Instrumenter
+
+	// removes secrets and personal data from logged bodies, see WithRedactor
+	redactor Redactor
+
+	// when set, requests and responses are logged with typed attributes instead of to log, see WithStructuredLogger
+	structuredLog StructuredLogger
+
+	// how often 202 Accepted responses are polled without a Retry-After, defaults to one second
+	asyncPollInterval time.Duration
+
+	// how long 202 Accepted responses are polled at most, defaults to five minutes
+	asyncPollTimeout time.Duration
+
+	// first interval between polls of a running bulk operation, defaults to one second
+	bulkPollInterval time.Duration
+
+	// called with the first deprecation notice of every endpoint, see WithDeprecationHandler
+	onDeprecation func(DeprecationNotice)
+
+	// mu guards the fields below which are updated after every request
+	mu sync.RWMutex
+
+	// number of attempts made by the most recently completed request
 	attempts int
 
+	// RateLimits holds the rate limits reported by the most recently completed
+	// request. It is updated concurrently when the client is shared between
+	// goroutines, prefer GetRateLimits over reading it directly.
 	RateLimits RateLimitInfo
 
+	// deprecated endpoints called by the client, keyed by endpoint
+	deprecations map[string]*DeprecationNotice
+
 	// Services used for communicating with the API
 	Product                    ProductService
 	CustomCollection           CustomCollectionService
@@ -131,6 +191,48 @@ type Client struct {
 	PaymentsTransactions       PaymentsTransactionsService
 	OrderRisk                  OrderRiskService
 	ApiPermissions             ApiPermissionsService
+	BulkOperation              BulkOperationService
+}
+
+// Sentinel errors matching a ResponseError, or an error wrapping it, of the
+// corresponding status with errors.Is:
+//
+//	if errors.Is(err, goshopify.ErrNotFound) {
+//		// the resource was deleted
+//	}
+var (
+	// ErrUnauthorized matches 401 Unauthorized, the access token is invalid
+	// or was revoked.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrPaymentRequired matches 402 Payment Required, the shop is frozen.
+	ErrPaymentRequired = errors.New("payment required")
+
+	// ErrForbidden matches 403 Forbidden, the app lacks a scope.
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrNotFound matches 404 Not Found.
+	ErrNotFound = errors.New("not found")
+
+	// ErrUnprocessable matches 422 Unprocessable Entity, see
+	// ResponseError.FieldErrors for the invalid fields.
+	ErrUnprocessable = errors.New("unprocessable entity")
+
+	// ErrLocked matches 423 Locked, the shop is locked.
+	ErrLocked = errors.New("locked")
+)
+
+// ErrShopFrozen is an alias of ErrPaymentRequired.
+var ErrShopFrozen = ErrPaymentRequired
+
+// statusErrors maps response statuses to their sentinel error
+var statusErrors = map[int]error{
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusPaymentRequired:     ErrPaymentRequired,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusUnprocessableEntity: ErrUnprocessable,
+	http.StatusLocked:              ErrLocked,
 }
 
 // A general response error that follows a similar layout to Shopify's response
@@ -139,6 +241,18 @@ type ResponseError struct {
 	Status  int
 	Message string
 	Errors  []string
+
+	// FieldErrors holds the messages of every field when Shopify reports
+	// errors by field, e.g. {"errors": {"title": ["can't be blank"]}}.
+	FieldErrors map[string][]string
+
+	// RequestId is Shopify's X-Request-Id of the response, to be quoted when
+	// contacting Shopify support.
+	RequestId string
+
+	// GraphQLErrors holds the errors of a graphql response, with their path,
+	// locations and code.
+	GraphQLErrors []GraphQLError
 }
 
 // GetStatus returns http  response status
@@ -156,6 +270,28 @@ func (e ResponseError) GetErrors() []string {
 	return e.Errors
 }
 
+// GetFieldErrors returns the response errors by field
+func (e ResponseError) GetFieldErrors() map[string][]string {
+	return e.FieldErrors
+}
+
+// GetRequestId returns the X-Request-Id of the response
+func (e ResponseError) GetRequestId() string {
+	return e.RequestId
+}
+
+// GetGraphQLErrors returns the errors of a graphql response
+func (e ResponseError) GetGraphQLErrors() []GraphQLError {
+	return e.GraphQLErrors
+}
+
+// Is reports whether target is the sentinel error of the response status, e.g.
+// ErrNotFound for 404 Not Found.
+func (e ResponseError) Is(target error) bool {
+	sentinel, ok := statusErrors[e.Status]
+	return ok && sentinel == target
+}
+
 func (e ResponseError) Error() string {
 	if e.Message != "" {
 		return e.Message
@@ -239,7 +375,12 @@ func (c *Client) NewRequest(ctx context.Context, method, relPath string, body, o
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("User-Agent", UserAgent)
 
-	if c.token != "" {
+	if c.storefrontToken != "" {
+		req.Header.Add(storefrontTokenHeader, c.storefrontToken)
+		if ip := buyerIPFromContext(ctx); ip != "" {
+			req.Header.Add(storefrontBuyerIPHeader, ip)
+		}
+	} else if c.token != "" {
 		req.Header.Add("X-Shopify-Access-Token", c.token)
 	} else if c.app.Password != "" {
 		req.SetBasicAuth(c.app.ApiKey, c.app.Password)
@@ -281,12 +422,14 @@ func NewClient(app App, shopName, token string, opts ...Option) (*Client, error)
 		Client: &http.Client{
 			Timeout: time.Second * defaultHttpTimeout,
 		},
-		log:        &LeveledLogger{},
-		app:        app,
-		baseURL:    baseURL,
-		token:      token,
-		apiVersion: defaultApiVersion,
-		pathPrefix: defaultApiPathPrefix,
+		log:             &LeveledLogger{},
+		redactor:        &JSONRedactor{Keys: DefaultRedactedKeys, Paths: DefaultRedactedPaths},
+		app:             app,
+		baseURL:         baseURL,
+		token:           token,
+		apiVersion:      defaultApiVersion,
+		pathPrefix:      defaultApiPathPrefix,
+		graphQLThrottle: newGraphQLThrottle(),
 	}
 
 	c.Product = &ProductServiceOp{client: c}
@@ -336,12 +479,21 @@ func NewClient(app App, shopName, token string, opts ...Option) (*Client, error)
 	c.PaymentsTransactions = &PaymentsTransactionsServiceOp{client: c}
 	c.OrderRisk = &OrderRiskServiceOp{client: c}
 	c.ApiPermissions = &ApiPermissionsServiceOp{client: c}
+	c.BulkOperation = &BulkOperationServiceOp{client: c}
 
 	// apply any options
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	// redirects are followed by awaitResponse, which only sends credentials to the shop
+	c.Client = stopAtRedirects(c.Client)
+
+	if c.apiVersion != defaultApiVersion && !c.skipApiVersionCheck && !validApiVersion(c.apiVersion) {
+		return nil, fmt.Errorf("invalid api version %q, expected a YYYY-MM version or %q", c.apiVersion, UnstableApiVersion)
+	}
+	c.warnApiVersionSupport(time.Now())
+
 	return c, nil
 }
 
@@ -357,12 +509,36 @@ func (c *Client) Do(req *http.Request, v interface{}) error {
 	return nil
 }
 
-// doGetHeaders executes a request, decoding the response into `v` and also returns any response headers.
+// doGetHeaders executes a request through the client's middleware, decoding the response into `v` and also returns any
+// response headers.
 func (c *Client) doGetHeaders(req *http.Request, v interface{}) (http.Header, error) {
+	handler := RequestHandler(c.send)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		handler = c.middleware[i](handler)
+	}
+
+	return handler(req, v)
+}
+
+// send executes a request, retrying it according to the retry policy, decoding the response into `v` and also returns
+// any response headers.
+func (c *Client) send(req *http.Request, v interface{}) (http.Header, error) {
 	var resp *http.Response
 	var err error
-	retries := c.retries
-	c.attempts = 0
+	attempts := 0
+	retryPolicy := c.retryPolicy
+	if retryPolicy == nil {
+		retryPolicy = &DefaultRetryPolicy{MaxAttempts: c.retries}
+	}
+	// GraphQL requests are limited by query cost, not the REST bucket
+	rateLimiter := c.rateLimiter
+	if strings.HasSuffix(req.URL.Path, "/graphql.json") {
+		rateLimiter = nil
+	}
+	defer func() { c.setAttempts(attempts) }()
+	if _, ok := v.(streamDecoder); ok {
+		req = req.WithContext(context.WithValue(req.Context(), streamedResponseKey{}, true))
+	}
 	c.logRequest(req)
 
 	// copy request body so it can be re-used
@@ -375,62 +551,94 @@ func (c *Client) doGetHeaders(req *http.Request, v interface{}) (http.Header, er
 		}
 	}
 
+	instrumenter := c.instrumenter
+	if instrumenter == nil {
+		instrumenter = noopInstrumenter{}
+	}
+	resource := resourceName(req.Method, req.URL.Path)
+
+	// retry backoff slept before the next attempt
+	var backoff time.Duration
+
+	start := time.Now()
 	for {
-		c.attempts++
-		req.Body = ioutil.NopCloser(bytes.NewBuffer(body))
-		resp, err = c.Client.Do(req)
-		c.logResponse(resp)
-		if err != nil {
-			return nil, err // http client errors, not api responses
+		attempts++
+
+		// time spent waiting on the retry backoff and rate limiter before this attempt only
+		waited := backoff
+		if rateLimiter != nil {
+			waitStart := time.Now()
+			if err = rateLimiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+			waited += time.Since(waitStart)
 		}
 
-		respErr := CheckResponseError(resp)
-		if respErr == nil {
-			break // no errors, break out of the retry loop
+		event := RequestEvent{
+			Resource:     resource,
+			Method:       req.Method,
+			Path:         req.URL.Path,
+			Attempt:      attempts,
+			ThrottleWait: waited,
 		}
+		ctx := instrumenter.RequestStart(req.Context(), event)
+		attemptStart := time.Now()
 
-		// retry scenario, close resp and any continue will retry
-		resp.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+		resp, err = c.Client.Do(req.WithContext(ctx))
+		c.logResponse(resp)
+		if err != nil {
+			// http client errors, not api responses
+			resp = nil
+		} else {
+			c.observeResponse(req, resp, rateLimiter)
 
-		if retries <= 1 {
-			return nil, respErr
+			// redirects and 202 responses are resolved into the final response
+			resp, err = c.awaitResponse(req.WithContext(ctx), resp, rateLimiter)
+			if err == nil {
+				err = CheckResponseError(resp)
+			}
 		}
 
-		if rateLimitErr, isRetryErr := respErr.(RateLimitError); isRetryErr {
-			// back off and retry
+		event.Duration = time.Since(attemptStart)
+		event.Err = err
+		if resp != nil {
+			event.StatusCode = resp.StatusCode
+			event.RequestId = resp.Header.Get("X-Request-Id")
+			event.RequestCount, event.BucketSize, _ = parseCallLimit(resp.Header)
+		}
+		instrumenter.RequestEnd(ctx, event)
 
-			wait := time.Duration(rateLimitErr.RetryAfter) * time.Second
-			c.log.Debugf("rate limited waiting %s", wait.String())
-			time.Sleep(wait)
-			retries--
-			continue
+		if err == nil {
+			break // no errors, break out of the retry loop
 		}
 
-		var doRetry bool
-		switch resp.StatusCode {
-		case http.StatusServiceUnavailable:
-			c.log.Debugf("service unavailable, retrying")
-			doRetry = true
-			retries--
+		// retry scenario, close resp and any continue will retry
+		if resp != nil {
+			resp.Body.Close()
 		}
 
-		if doRetry {
-			continue
+		retry, wait := retryPolicy.ShouldRetry(req, resp, err, attempts, time.Since(start))
+		if !retry {
+			return nil, err
 		}
 
-		// no retry attempts, just return the err
-		return nil, respErr
+		c.log.Debugf("retrying in %s after error: %v", wait.String(), err)
+		if sleepErr := sleepContext(req.Context(), wait); sleepErr != nil {
+			return nil, sleepErr
+		}
+		backoff = wait
 	}
 
 	defer resp.Body.Close()
 
-	if c.apiVersion == defaultApiVersion && resp.Header.Get("X-Shopify-API-Version") != "" {
-		// if using stable on first request set the api version
-		c.apiVersion = resp.Header.Get("X-Shopify-API-Version")
-		c.log.Infof("api version not set, now using %s", c.apiVersion)
-	}
+	c.recordApiVersion(resp.Header.Get("X-Shopify-API-Version"))
 
-	if v != nil {
+	if stream, ok := v.(streamDecoder); ok {
+		if err := stream.decodeStream(resp.Body); err != nil {
+			return nil, err
+		}
+	} else if v != nil {
 		decoder := json.NewDecoder(resp.Body)
 		err := decoder.Decode(&v)
 		if err != nil {
@@ -438,20 +646,91 @@ func (c *Client) doGetHeaders(req *http.Request, v interface{}) (http.Header, er
 		}
 	}
 
-	if s := strings.Split(resp.Header.Get("X-Shopify-Shop-Api-Call-Limit"), "/"); len(s) == 2 {
-		c.RateLimits.RequestCount, _ = strconv.Atoi(s[0])
-		c.RateLimits.BucketSize, _ = strconv.Atoi(s[1])
+	c.updateRateLimits(resp.Header)
+
+	return resp.Header, nil
+}
+
+// observeResponse updates the rate limiter and collects deprecation notices
+// from the headers of every response, including intermediate ones.
+func (c *Client) observeResponse(req *http.Request, resp *http.Response, rateLimiter RateLimiter) {
+	if rateLimiter != nil {
+		if count, size, ok := parseCallLimit(resp.Header); ok {
+			rateLimiter.Observe(count, size)
+		}
+	}
+	c.recordDeprecation(req, resp)
+}
+
+// setAttempts records the number of attempts made by the last request.
+func (c *Client) setAttempts(attempts int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts = attempts
+}
+
+// recordApiVersion records the api version reported by Shopify when the
+// client was created without an explicit version. The client keeps using the
+// unversioned path rather than switching to the reported version.
+func (c *Client) recordApiVersion(version string) {
+	if version == "" || c.apiVersion != defaultApiVersion {
+		return
 	}
 
-	c.RateLimits.RetryAfterSeconds, _ = strconv.ParseFloat(resp.Header.Get("Retry-After"), 64)
+	c.mu.Lock()
+	changed := version != c.servedApiVersion
+	c.servedApiVersion = version
+	c.mu.Unlock()
 
-	return resp.Header, nil
+	if changed {
+		c.log.Infof("api version not set, requests are served by %s, pin a version with WithVersion", version)
+	}
+}
+
+// updateRateLimits stores the REST rate limit headers of a response.
+func (c *Client) updateRateLimits(header http.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if count, size, ok := parseCallLimit(header); ok {
+		c.RateLimits.RequestCount = count
+		c.RateLimits.BucketSize = size
+	}
+
+	c.RateLimits.RetryAfterSeconds, _ = strconv.ParseFloat(header.Get("Retry-After"), 64)
+}
+
+// updateGraphQLCost stores the cost extension of a GraphQL response.
+func (c *Client) updateGraphQLCost(cost GraphQLCost) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.RateLimits.GraphQLCost = &cost
+	c.RateLimits.RetryAfterSeconds = cost.RetryAfterSeconds()
+}
+
+// GetRateLimits returns a snapshot of the rate limits reported by the most
+// recently completed request. It is safe to call while other goroutines are
+// using the client.
+func (c *Client) GetRateLimits() RateLimitInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	limits := c.RateLimits
+	if limits.GraphQLCost != nil {
+		cost := *limits.GraphQLCost
+		limits.GraphQLCost = &cost
+	}
+	return limits
 }
 
 func (c *Client) logRequest(req *http.Request) {
 	if req == nil {
 		return
 	}
+	if c.structuredLog != nil {
+		c.logStructuredRequest(req)
+		return
+	}
 	if req.URL != nil {
 		c.log.Debugf("%s: %s", req.Method, req.URL.String())
 	}
@@ -462,24 +741,106 @@ func (c *Client) logResponse(res *http.Response) {
 	if res == nil {
 		return
 	}
+	if c.structuredLog != nil {
+		c.logStructuredResponse(res)
+		return
+	}
 
 	c.log.Debugf("Shopify X-Request-Id: %s", res.Header.Get("X-Request-Id"))
 	c.log.Debugf("RECV %d: %s", res.StatusCode, res.Status)
-	c.logBody(&res.Body, "RESP: %s")
+	if logsResponseBody(res) {
+		c.logBody(&res.Body, "RESP: %s")
+	}
 }
 
 func (c *Client) logBody(body *io.ReadCloser, format string) {
-	if body == nil {
+	if !c.debugEnabled() {
 		return
 	}
-	b, err := ioutil.ReadAll(*body)
-	if err != nil && !errors.Is(err, io.EOF) {
+	if b := c.readLoggedBody(body); len(b) > 0 {
+		c.log.Debugf(format, string(b))
+	}
+}
+
+// logStructuredRequest logs a request with typed attributes to the structured logger.
+func (c *Client) logStructuredRequest(req *http.Request) {
+	ctx := req.Context()
+	if !c.structuredLog.Enabled(ctx, LevelDebug) {
 		return
 	}
-	if len(b) > 0 {
-		c.log.Debugf(format, string(b))
+
+	attrs := []LogAttr{
+		{Key: "shop", Value: c.baseURL.Host},
+		{Key: "method", Value: req.Method},
+	}
+	if req.URL != nil {
+		attrs = append(attrs, LogAttr{Key: "path", Value: req.URL.Path})
+	}
+	if b := c.readLoggedBody(&req.Body); len(b) > 0 {
+		attrs = append(attrs, LogAttr{Key: "body", Value: string(b)})
+	}
+
+	c.structuredLog.Log(ctx, LevelDebug, "shopify request", attrs...)
+}
+
+// logStructuredResponse logs a response with typed attributes to the structured logger. Error responses are logged
+// as warnings, the body is only logged at debug level and not for streamed responses.
+func (c *Client) logStructuredResponse(res *http.Response) {
+	ctx := context.Background()
+	attrs := []LogAttr{{Key: "shop", Value: c.baseURL.Host}}
+	if res.Request != nil {
+		ctx = res.Request.Context()
+		attrs = append(attrs, LogAttr{Key: "method", Value: res.Request.Method})
+		if res.Request.URL != nil {
+			attrs = append(attrs, LogAttr{Key: "path", Value: res.Request.URL.Path})
+		}
+	}
+
+	level := LevelDebug
+	if res.StatusCode >= http.StatusBadRequest {
+		level = LevelWarn
+	}
+	if !c.structuredLog.Enabled(ctx, level) {
+		return
+	}
+
+	attrs = append(attrs,
+		LogAttr{Key: "status", Value: res.StatusCode},
+		LogAttr{Key: "request_id", Value: res.Header.Get("X-Request-Id")},
+	)
+	if c.structuredLog.Enabled(ctx, LevelDebug) && logsResponseBody(res) {
+		if b := c.readLoggedBody(&res.Body); len(b) > 0 {
+			attrs = append(attrs, LogAttr{Key: "body", Value: string(b)})
+		}
+	}
+
+	c.structuredLog.Log(ctx, level, "shopify response", attrs...)
+}
+
+// readLoggedBody reads a request or response body, leaving it readable, and returns it redacted for logging.
+func (c *Client) readLoggedBody(body *io.ReadCloser) []byte {
+	if body == nil || *body == nil {
+		return nil
+	}
+	b, err := ioutil.ReadAll(*body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil
 	}
 	*body = ioutil.NopCloser(bytes.NewBuffer(b))
+
+	if len(b) > 0 && c.redactor != nil {
+		return c.redactor.Redact(b)
+	}
+	return b
+}
+
+// debugEnabled reports whether debug messages are logged, so that bodies are
+// only read and redacted when they will be written.
+func (c *Client) debugEnabled() bool {
+	if l, ok := c.log.(*LeveledLogger); ok {
+		return l.Level >= LevelDebug
+	}
+	return true
 }
 
 func wrapSpecificError(r *http.Response, err ResponseError) error {
@@ -492,12 +853,6 @@ func wrapSpecificError(r *http.Response, err ResponseError) error {
 		}
 	}
 
-	// if err.Status == http.StatusSeeOther {
-	// todo
-	// The response to the request can be found under a different URL in the
-	// Location header and can be retrieved using a GET method on that resource.
-	// }
-
 	if err.Status == http.StatusNotAcceptable {
 		err.Message = http.StatusText(err.Status)
 	}
@@ -536,8 +891,9 @@ func CheckResponseError(r *http.Response) error {
 
 	// Create the response error from the Shopify error.
 	responseError := ResponseError{
-		Status:  r.StatusCode,
-		Message: shopifyError.Error,
+		Status:    r.StatusCode,
+		Message:   shopifyError.Error,
+		RequestId: r.Header.Get("X-Request-Id"),
 	}
 
 	// If the errors field is not filled out, we can return here.
@@ -555,6 +911,7 @@ func CheckResponseError(r *http.Response) error {
 	// }
 	// This structure is flattened to a single array:
 	// [ "title: something is wrong" ]
+	// and kept by field in FieldErrors.
 	//
 	// Unfortunately, "errors" can also be a single string so we have to deal
 	// with that. Lots of reflection :-(
@@ -572,7 +929,11 @@ func CheckResponseError(r *http.Response) error {
 	case reflect.Map:
 		// A map, parse each error for each key in the map.
 		// json always serializes into map[string]interface{} for objects
+		responseError.FieldErrors = make(map[string][]string)
 		for k, v := range shopifyError.Errors.(map[string]interface{}) {
+			if v == nil {
+				continue
+			}
 			switch reflect.TypeOf(v).Kind() {
 			// Check to make sure the interface is a slice
 			// json always serializes JSON arrays into []interface{}
@@ -585,6 +946,7 @@ func CheckResponseError(r *http.Response) error {
 					}
 					topicAndElem := fmt.Sprintf("%v: %v", k, elem)
 					responseError.Errors = append(responseError.Errors, topicAndElem)
+					responseError.FieldErrors[k] = append(responseError.FieldErrors[k], fmt.Sprint(elem))
 				}
 			case reflect.String:
 				elem := v.(string)
@@ -593,6 +955,7 @@ func CheckResponseError(r *http.Response) error {
 				}
 				topicAndElem := fmt.Sprintf("%v: %v", k, elem)
 				responseError.Errors = append(responseError.Errors, topicAndElem)
+				responseError.FieldErrors[k] = append(responseError.FieldErrors[k], elem)
 			}
 		}
 	}
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -41,6 +42,13 @@ func (errReader) Close() error {
 	return nil
 }
 
+// skipApiVersionCheck lets tests use testApiVersion, which isn't a valid month
+func skipApiVersionCheck() Option {
+	return func(c *Client) {
+		c.skipApiVersionCheck = true
+	}
+}
+
 func setup() {
 	app = App{
 		ApiKey:      "apikey",
@@ -50,7 +58,7 @@ func setup() {
 		Password:    "privateapppassword",
 	}
 	client = MustNewClient(app, "fooshop", "abcd",
-		WithVersion(testApiVersion),
+		WithVersion(testApiVersion), skipApiVersionCheck(),
 		WithRetry(maxRetries))
 	httpmock.ActivateNonDefault(client.Client)
 }
@@ -68,7 +76,7 @@ func loadFixture(filename string) []byte {
 }
 
 func TestNewClient(t *testing.T) {
-	testClient := MustNewClient(app, "fooshop", "abcd", WithVersion(testApiVersion))
+	testClient := MustNewClient(app, "fooshop", "abcd", WithVersion(testApiVersion), skipApiVersionCheck())
 	expected := "https://fooshop.myshopify.com"
 	if testClient.baseURL.String() != expected {
 		t.Errorf("MustNewClient BaseURL = %v, expected %v", testClient.baseURL.String(), expected)
@@ -76,7 +84,7 @@ func TestNewClient(t *testing.T) {
 }
 
 func TestNewClientWithNoToken(t *testing.T) {
-	testClient := MustNewClient(app, "fooshop", "", WithVersion(testApiVersion))
+	testClient := MustNewClient(app, "fooshop", "", WithVersion(testApiVersion), skipApiVersionCheck())
 	expected := "https://fooshop.myshopify.com"
 	if testClient.baseURL.String() != expected {
 		t.Errorf("MustNewClient BaseURL = %v, expected %v", testClient.baseURL.String(), expected)
@@ -84,7 +92,7 @@ func TestNewClientWithNoToken(t *testing.T) {
 }
 
 func TestAppNewClient(t *testing.T) {
-	testClient, _ := app.NewClient("fooshop", "abcd", WithVersion(testApiVersion))
+	testClient, _ := app.NewClient("fooshop", "abcd", WithVersion(testApiVersion), skipApiVersionCheck())
 	expected := "https://fooshop.myshopify.com"
 	if testClient.baseURL.String() != expected {
 		t.Errorf("MustNewClient BaseURL = %v, expected %v", testClient.baseURL.String(), expected)
@@ -92,7 +100,7 @@ func TestAppNewClient(t *testing.T) {
 }
 
 func TestAppNewClientWithNoToken(t *testing.T) {
-	testClient, _ := app.NewClient("fooshop", "", WithVersion(testApiVersion))
+	testClient, _ := app.NewClient("fooshop", "", WithVersion(testApiVersion), skipApiVersionCheck())
 	expected := "https://fooshop.myshopify.com"
 	if testClient.baseURL.String() != expected {
 		t.Errorf("MustNewClient BaseURL = %v, expected %v", testClient.baseURL.String(), expected)
@@ -113,13 +121,13 @@ func TestBadShopNamePanic(t *testing.T) {
 			"foo, shop, stuff commas",
 		} {
 			tried = shopName
-			_ = MustNewClient(app, shopName, "abcd", WithVersion(testApiVersion))
+			_ = MustNewClient(app, shopName, "abcd", WithVersion(testApiVersion), skipApiVersionCheck())
 		}
 	}()
 }
 
 func TestNewRequest(t *testing.T) {
-	testClient := MustNewClient(app, "fooshop", "abcd", WithVersion(testApiVersion))
+	testClient := MustNewClient(app, "fooshop", "abcd", WithVersion(testApiVersion), skipApiVersionCheck())
 
 	inURL, outURL := "foo?page=1", "https://fooshop.myshopify.com/foo?limit=10&page=1"
 	inBody := struct {
@@ -162,7 +170,7 @@ func TestNewRequest(t *testing.T) {
 }
 
 func TestNewRequestForPrivateApp(t *testing.T) {
-	testClient := MustNewClient(app, "fooshop", "", WithVersion(testApiVersion))
+	testClient := MustNewClient(app, "fooshop", "", WithVersion(testApiVersion), skipApiVersionCheck())
 
 	inURL, outURL := "foo?page=1", "https://fooshop.myshopify.com/foo?limit=10&page=1"
 	inBody := struct {
@@ -219,7 +227,7 @@ func TestNewRequestForPrivateApp(t *testing.T) {
 }
 
 func TestNewRequestMissingToken(t *testing.T) {
-	testClient := MustNewClient(app, "fooshop", "", WithVersion(testApiVersion))
+	testClient := MustNewClient(app, "fooshop", "", WithVersion(testApiVersion), skipApiVersionCheck())
 
 	req, _ := testClient.NewRequest(context.Background(), "GET", "/foo", nil, nil)
 
@@ -231,7 +239,7 @@ func TestNewRequestMissingToken(t *testing.T) {
 }
 
 func TestNewRequestError(t *testing.T) {
-	testClient := MustNewClient(app, "fooshop", "abcd", WithVersion(testApiVersion))
+	testClient := MustNewClient(app, "fooshop", "abcd", WithVersion(testApiVersion), skipApiVersionCheck())
 
 	cases := []struct {
 		method  string
@@ -280,7 +288,12 @@ func TestDo(t *testing.T) {
 		{
 			"foo/3",
 			httpmock.NewStringResponder(400, `{"errors": {"title": ["wrong"]}}`),
-			ResponseError{Status: 400, Message: "title: wrong", Errors: []string{"title: wrong"}},
+			ResponseError{
+				Status:      400,
+				Message:     "title: wrong",
+				Errors:      []string{"title: wrong"},
+				FieldErrors: map[string][]string{"title": {"wrong"}},
+			},
 		},
 		{
 			"foo/4",
@@ -528,8 +541,6 @@ func TestClientDoAutoApiVersion(t *testing.T) {
 		resp.Header.Add("X-Shopify-API-Version", testApiVersion)
 		return resp, nil
 	}
-	expected := testApiVersion
-
 	testClient := MustNewClient(app, "fooshop", "abcd")
 	httpmock.ActivateNonDefault(testClient.Client)
 	shopUrl := fmt.Sprintf("https://fooshop.myshopify.com/%v", u)
@@ -545,10 +556,16 @@ func TestClientDoAutoApiVersion(t *testing.T) {
 		t.Errorf("TestClientDoApiVersion(): errored %s", err)
 	}
 
-	if expected != testClient.apiVersion {
+	if testClient.servedApiVersion != testApiVersion {
 		t.Errorf(
 			"TestClientDoApiVersion(): client unable to get API Version from X-Shopify-API-Version: expected %s received %s",
-			expected, testClient.apiVersion)
+			testApiVersion, testClient.servedApiVersion)
+	}
+
+	// the version isn't switched by the response
+	if testClient.apiVersion != defaultApiVersion || testClient.pathPrefix != defaultApiPathPrefix {
+		t.Errorf("TestClientDoApiVersion(): client switched to version %s with path prefix %s",
+			testClient.apiVersion, testClient.pathPrefix)
 	}
 }
 
@@ -822,6 +839,63 @@ func TestCheckResponseError(t *testing.T) {
 	}
 }
 
+func TestCheckResponseErrorFieldErrors(t *testing.T) {
+	resp := httpmock.NewStringResponse(422, `{"errors": {"title": ["can't be blank", "is too short"], "price": "must be positive"}}`)
+	resp.Header.Set("X-Request-Id", "req-1")
+
+	err := CheckResponseError(resp)
+
+	var respErr ResponseError
+	if !errors.As(err, &respErr) {
+		t.Fatalf("CheckResponseError returned %T, expected ResponseError", err)
+	}
+
+	expected := map[string][]string{
+		"title": {"can't be blank", "is too short"},
+		"price": {"must be positive"},
+	}
+	if !reflect.DeepEqual(respErr.GetFieldErrors(), expected) {
+		t.Errorf("ResponseError.FieldErrors = %#v, expected %#v", respErr.FieldErrors, expected)
+	}
+	if respErr.GetRequestId() != "req-1" {
+		t.Errorf("ResponseError.RequestId = %q, expected req-1", respErr.RequestId)
+	}
+}
+
+func TestResponseErrorIs(t *testing.T) {
+	cases := []struct {
+		status   int
+		expected error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusPaymentRequired, ErrPaymentRequired},
+		{http.StatusPaymentRequired, ErrShopFrozen},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusUnprocessableEntity, ErrUnprocessable},
+		{http.StatusLocked, ErrLocked},
+	}
+
+	for _, c := range cases {
+		err := fmt.Errorf("wrapped: %w", ResponseError{Status: c.status})
+		if !errors.Is(err, c.expected) {
+			t.Errorf("errors.Is(%d, %v) = false, expected true", c.status, c.expected)
+		}
+		if errors.Is(ResponseError{Status: http.StatusInternalServerError}, c.expected) {
+			t.Errorf("errors.Is(500, %v) = true, expected false", c.expected)
+		}
+	}
+
+	if errors.Is(ResponseError{Status: http.StatusNotFound}, ErrForbidden) {
+		t.Error("errors.Is(404, ErrForbidden) = true, expected false")
+	}
+
+	// rate limit errors embed the response error
+	if !errors.Is(RateLimitError{ResponseError: ResponseError{Status: http.StatusLocked}}, ErrLocked) {
+		t.Error("errors.Is(RateLimitError, ErrLocked) = false, expected true")
+	}
+}
+
 func TestCount(t *testing.T) {
 	setup()
 	defer teardown()
@@ -996,3 +1070,71 @@ func TestListWithPagination(t *testing.T) {
 		t.Fatalf("Expected prev page: %s   got: %s", "123", pagination.PreviousPageOptions.PageInfo)
 	}
 }
+
+func TestClientConcurrentUse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/products/count.json", client.pathPrefix),
+		createResponderWithHeaders(200, `{"count": 3}`, map[string]string{
+			"X-Shopify-Shop-Api-Call-Limit": "1/40",
+			"X-Shopify-API-Version":         testApiVersion,
+		}))
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders/count.json", client.pathPrefix),
+		createResponderWithHeaders(200, `{"count": 7}`, map[string]string{
+			"X-Shopify-Shop-Api-Call-Limit": "2/40",
+		}))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{"foo":"bar"},"extensions":{"cost":{"requestedQueryCost":1,"actualQueryCost":1,"throttleStatus":{"maximumAvailable":1000,"currentlyAvailable":999,"restoreRate":50}}}}`))
+
+	const workers = 10
+	errs := make(chan error, workers*3)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			cnt, err := client.Product.Count(context.Background(), nil)
+			if err == nil && cnt != 3 {
+				err = fmt.Errorf("Product.Count returned %d, expected 3", cnt)
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			cnt, err := client.Order.Count(context.Background(), nil)
+			if err == nil && cnt != 7 {
+				err = fmt.Errorf("Order.Count returned %d, expected 7", cnt)
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			resp := struct {
+				Foo string `json:"foo"`
+			}{}
+			err := client.GraphQL.Query(context.Background(), "query {}", nil, &resp)
+			if err == nil && resp.Foo != "bar" {
+				err = fmt.Errorf("GraphQL.Query returned %s, expected bar", resp.Foo)
+			}
+			_ = client.GetRateLimits()
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	limits := client.GetRateLimits()
+	if limits.BucketSize != 40 {
+		t.Errorf("GetRateLimits().BucketSize = %d, expected 40", limits.BucketSize)
+	}
+	if limits.GraphQLCost == nil || limits.GraphQLCost.ThrottleStatus.MaximumAvailable != 1000 {
+		t.Errorf("GetRateLimits().GraphQLCost = %#v, expected the last GraphQL cost", limits.GraphQLCost)
+	}
+}
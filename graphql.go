@@ -2,7 +2,12 @@ package goshopify
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"math"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -11,17 +16,21 @@ import (
 // See https://shopify.dev/docs/admin-api/graphql/reference
 type GraphQLService interface {
 	Query(context.Context, string, interface{}, interface{}) error
+	Mutate(ctx context.Context, m string, vars, resp interface{}) error
+	QueryStruct(ctx context.Context, q interface{}, vars map[string]interface{}) error
+	MutateStruct(ctx context.Context, m interface{}, vars map[string]interface{}) error
+	QueryConnection(ctx context.Context, q string, vars map[string]interface{}, cursorVar, path string, fn func(node json.RawMessage) error) error
 }
 
 // GraphQLServiceOp handles communication with the graphql endpoint of
-// the Shopify API.
+// the Shopify API. Like every other service it is safe for concurrent use.
 type GraphQLServiceOp struct {
 	client *Client
 }
 
 type graphQLResponse struct {
 	Data       interface{}        `json:"data"`
-	Errors     []graphQLError     `json:"errors"`
+	Errors     []GraphQLError     `json:"errors"`
 	Extensions *graphQLExtensions `json:"extensions"`
 }
 
@@ -43,28 +52,91 @@ type GraphQLThrottleStatus struct {
 	RestoreRate        float64 `json:"restoreRate"`
 }
 
-type graphQLError struct {
-	Message    string                  `json:"message"`
-	Extensions *graphQLErrorExtensions `json:"extensions"`
-	Locations  []graphQLErrorLocation  `json:"locations"`
+// GraphQLError is an error of a graphql response, such as a syntax error of
+// the query, an invalid variable or an error resolving a field. Query returns
+// them in the GraphQLErrors of a ResponseError.
+type GraphQLError struct {
+	Message string `json:"message"`
+
+	// Path is the path of the field the error occurred on, of field names and
+	// list indexes, e.g. ["products", "edges", 0, "node", "metafield"].
+	Path       []interface{}           `json:"path,omitempty"`
+	Locations  []GraphQLErrorLocation  `json:"locations,omitempty"`
+	Extensions *GraphQLErrorExtensions `json:"extensions,omitempty"`
+}
+
+func (e GraphQLError) Error() string {
+	return e.Message
+}
+
+// GraphQLErrorLocation is the location of an error in the query.
+type GraphQLErrorLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLErrorExtensions holds the code of an error, e.g. THROTTLED,
+// ACCESS_DENIED or MAX_COST_EXCEEDED.
+type GraphQLErrorExtensions struct {
+	Code          string `json:"code"`
+	Documentation string `json:"documentation,omitempty"`
+}
+
+// Code returns the code of the error's extensions, if any.
+func (e GraphQLError) Code() string {
+	if e.Extensions == nil {
+		return ""
+	}
+	return e.Extensions.Code
+}
+
+// GraphQLUserError is an error in the input of a mutation, as returned in its
+// userErrors field. Only the user errors of some mutations have a code, e.g.
+// TAKEN or INVALID, it isn't selected by struct queries.
+type GraphQLUserError struct {
+	Field   []string `json:"field"`
+	Message string   `json:"message"`
+	Code    string   `json:"code,omitempty" graphql:"-"`
+}
+
+// GraphQLUserErrors is returned when a mutation reports userErrors, see
+// Mutate.
+type GraphQLUserErrors []GraphQLUserError
+
+// HasCode reports whether any of the errors has the code.
+func (e GraphQLUserErrors) HasCode(code string) bool {
+	for _, err := range e {
+		if err.Code == code {
+			return true
+		}
+	}
+	return false
 }
 
-type graphQLErrorExtensions struct {
-	Code          string
-	Documentation string
+func (e GraphQLUserErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		if len(err.Field) > 0 {
+			messages = append(messages, fmt.Sprintf("%s: %s", strings.Join(err.Field, "."), err.Message))
+		} else {
+			messages = append(messages, err.Message)
+		}
+	}
+	return strings.Join(messages, ", ")
 }
 
 const (
 	graphQLErrorCodeThrottled = "THROTTLED"
 )
 
-type graphQLErrorLocation struct {
-	Line   int `json:"line"`
-	Column int `json:"column"`
-}
+// matches the name of a named query, e.g. "query products($first: Int)"
+var graphQLOperationRegex = regexp.MustCompile(`^\s*(query|mutation|subscription)\s+([_A-Za-z][_0-9A-Za-z]*)`)
 
 // Query creates a graphql query against the Shopify API
-// the "data" portion of the response is unmarshalled into resp
+// the "data" portion of the response is unmarshalled into resp.
+// The cost of each query is tracked across calls and a query is delayed until
+// the shop is estimated to have enough points available for it, rather than
+// being sent only to be throttled.
 func (s *GraphQLServiceOp) Query(ctx context.Context, q string, vars, resp interface{}) error {
 	data := struct {
 		Query     string      `json:"query"`
@@ -74,61 +146,163 @@ func (s *GraphQLServiceOp) Query(ctx context.Context, q string, vars, resp inter
 		Variables: vars,
 	}
 
+	instrumenter := s.client.instrumenter
+	if instrumenter == nil {
+		instrumenter = noopInstrumenter{}
+	}
+	operation := graphQLOperationName(q)
 	attempts := 0
 
 	for {
+		// internal attempts count towards outer total
+		attempts += 1
+
+		// wait for the estimated cost of the query to be available
+		waitStart := time.Now()
+		reserved, err := s.client.graphQLThrottle.Wait(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		event := GraphQLEvent{
+			Operation:    operation,
+			Attempt:      attempts,
+			ThrottleWait: time.Since(waitStart),
+		}
+		queryCtx := instrumenter.GraphQLStart(ctx, event)
+		queryStart := time.Now()
+
 		gr := graphQLResponse{
 			Data: resp,
 		}
 
-		err := s.client.Post(ctx, "graphql.json", data, &gr)
-
-		// internal attempts count towards outer total
-		attempts += 1
+		err = s.client.Post(queryCtx, "graphql.json", data, &gr)
 
 		var retryAfterSecs float64
 
 		if gr.Extensions != nil {
 			retryAfterSecs = gr.Extensions.Cost.RetryAfterSeconds()
-			s.client.RateLimits.GraphQLCost = &gr.Extensions.Cost
-			s.client.RateLimits.RetryAfterSeconds = retryAfterSecs
+			s.client.updateGraphQLCost(gr.Extensions.Cost)
+			s.client.graphQLThrottle.observe(q, gr.Extensions.Cost)
+			event.Cost = &gr.Extensions.Cost
+		} else {
+			s.client.graphQLThrottle.refund(reserved)
 		}
 
+		var doRetry bool
 		if len(gr.Errors) > 0 {
-			responseError := ResponseError{Status: 200}
-			var doRetry bool
-
-			for _, err := range gr.Errors {
-				if err.Extensions != nil && err.Extensions.Code == graphQLErrorCodeThrottled {
-					if attempts >= s.client.retries {
-						return RateLimitError{
-							RetryAfter: int(math.Ceil(retryAfterSecs)),
-							ResponseError: ResponseError{
-								Status:  200,
-								Message: err.Message,
-							},
-						}
-					}
-
-					// only need to retry graphql throttled retries
-					doRetry = true
-				}
+			doRetry, err = s.responseError(gr.Errors, attempts, retryAfterSecs)
+		}
 
-				responseError.Errors = append(responseError.Errors, err.Message)
-			}
+		event.Duration = time.Since(queryStart)
+		event.Err = err
+		instrumenter.GraphQLEnd(queryCtx, event)
 
-			if doRetry {
-				wait := time.Duration(math.Ceil(retryAfterSecs)) * time.Second
-				s.client.log.Debugf("rate limited waiting %s", wait.String())
-				time.Sleep(wait)
-				continue
+		if doRetry {
+			// the throttle waits for the points to be restored before retrying
+			s.client.log.Debugf("rate limited waiting %.2fs", retryAfterSecs)
+			continue
+		}
+
+		return err
+	}
+}
+
+// responseError converts the errors of a graphql response into a ResponseError, or a RateLimitError once a
+// throttled query is out of retries. It also reports whether the query was throttled and should be retried.
+func (s *GraphQLServiceOp) responseError(errs []GraphQLError, attempts int, retryAfterSecs float64) (bool, error) {
+	responseError := ResponseError{Status: 200, GraphQLErrors: errs}
+	var doRetry bool
+
+	for _, err := range errs {
+		if err.Code() == graphQLErrorCodeThrottled {
+			if attempts >= s.client.retries {
+				return false, RateLimitError{
+					RetryAfter: int(math.Ceil(retryAfterSecs)),
+					ResponseError: ResponseError{
+						Status:        200,
+						Message:       err.Message,
+						GraphQLErrors: errs,
+					},
+				}
 			}
 
-			err = responseError
+			// only need to retry graphql throttled retries
+			doRetry = true
 		}
 
+		responseError.Errors = append(responseError.Errors, err.Message)
+	}
+
+	return doRetry, responseError
+}
+
+// Mutate runs a mutation like Query and unmarshals the "data" portion of the
+// response into resp. When the payload of the mutation reports userErrors, or
+// errors of a field ending in UserErrors such as customerUserErrors, they are
+// returned as GraphQLUserErrors once resp was unmarshalled:
+//
+//	err := client.GraphQL.Mutate(ctx, m, vars, &resp)
+//	var userErrors goshopify.GraphQLUserErrors
+//	if errors.As(err, &userErrors) && userErrors.HasCode("TAKEN") {
+//		// the handle is already used
+//	}
+//
+// The code of user errors is only set when the mutation selects it.
+func (s *GraphQLServiceOp) Mutate(ctx context.Context, m string, vars, resp interface{}) error {
+	var data json.RawMessage
+	if err := s.Query(ctx, m, vars, &data); err != nil {
 		return err
 	}
+	if resp != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, resp); err != nil {
+			return err
+		}
+	}
+	return mutationUserErrors(data)
+}
+
+// mutationUserErrors returns the user errors of the payloads of the data of a
+// mutation as GraphQLUserErrors, or nil when there are none
+func mutationUserErrors(data json.RawMessage) error {
+	var payloads map[string]json.RawMessage
+	if len(data) == 0 || json.Unmarshal(data, &payloads) != nil {
+		return nil
+	}
+
+	var userErrors GraphQLUserErrors
+	for _, key := range sortedKeys(payloads) {
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(payloads[key], &fields) != nil {
+			// a scalar or null payload
+			continue
+		}
+
+		for _, name := range sortedKeys(fields) {
+			if name != "userErrors" && !strings.HasSuffix(name, "UserErrors") {
+				continue
+			}
+			var errs GraphQLUserErrors
+			if err := json.Unmarshal(fields[name], &errs); err != nil {
+				return err
+			}
+			userErrors = append(userErrors, errs...)
+		}
+	}
+
+	if len(userErrors) == 0 {
+		return nil
+	}
+	return userErrors
+}
+
+func sortedKeys(object map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // RetryAfterSeconds returns the estimated retry after seconds based on
@@ -148,3 +322,13 @@ func (c GraphQLCost) RetryAfterSeconds() float64 {
 
 	return 0
 }
+
+// graphQLOperationName returns the name of a named query or mutation, or
+// "anonymous" when it has none.
+func graphQLOperationName(q string) string {
+	match := graphQLOperationRegex.FindStringSubmatch(q)
+	if len(match) != 3 {
+		return "anonymous"
+	}
+	return match[2]
+}
@@ -0,0 +1,455 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// BulkOperationService is an interface to run GraphQL bulk operations, which
+// export the results of a query, or run a mutation with every line of an
+// uploaded JSONL file of variables, asynchronously to a JSONL file.
+// See https://shopify.dev/docs/api/usage/bulk-operations/queries
+// and https://shopify.dev/docs/api/usage/bulk-operations/imports
+type BulkOperationService interface {
+	RunQuery(ctx context.Context, query string) (*BulkOperation, error)
+	RunMutation(ctx context.Context, mutation, stagedUploadPath string) (*BulkOperation, error)
+	UploadVariables(ctx context.Context, variables io.Reader) (string, error)
+	Current(ctx context.Context, operationType BulkOperationType) (*BulkOperation, error)
+	Cancel(ctx context.Context, id string) (*BulkOperation, error)
+	Wait(ctx context.Context, operation *BulkOperation) (*BulkOperation, error)
+	Download(ctx context.Context, operation *BulkOperation) (io.ReadCloser, error)
+}
+
+// BulkOperationServiceOp handles communication with the bulk operation
+// mutations and queries of the GraphQL Admin API.
+type BulkOperationServiceOp struct {
+	client *Client
+}
+
+// BulkOperationType is the type of a bulk operation.
+type BulkOperationType string
+
+const (
+	BulkOperationTypeQuery    BulkOperationType = "QUERY"
+	BulkOperationTypeMutation BulkOperationType = "MUTATION"
+)
+
+// BulkOperationStatus is the status of a bulk operation.
+type BulkOperationStatus string
+
+const (
+	BulkOperationStatusCreated   BulkOperationStatus = "CREATED"
+	BulkOperationStatusRunning   BulkOperationStatus = "RUNNING"
+	BulkOperationStatusCompleted BulkOperationStatus = "COMPLETED"
+	BulkOperationStatusCanceling BulkOperationStatus = "CANCELING"
+	BulkOperationStatusCanceled  BulkOperationStatus = "CANCELED"
+	BulkOperationStatusFailed    BulkOperationStatus = "FAILED"
+	BulkOperationStatusExpired   BulkOperationStatus = "EXPIRED"
+)
+
+// BulkOperationErrorCode is the reason a bulk operation failed.
+type BulkOperationErrorCode string
+
+const (
+	BulkOperationErrorCodeAccessDenied        BulkOperationErrorCode = "ACCESS_DENIED"
+	BulkOperationErrorCodeInternalServerError BulkOperationErrorCode = "INTERNAL_SERVER_ERROR"
+	BulkOperationErrorCodeTimeout             BulkOperationErrorCode = "TIMEOUT"
+)
+
+// BulkOperation represents a Shopify bulk operation
+type BulkOperation struct {
+	Id             string                 `json:"id"`
+	Type           BulkOperationType      `json:"type"`
+	Status         BulkOperationStatus    `json:"status"`
+	ErrorCode      BulkOperationErrorCode `json:"errorCode"`
+	CreatedAt      *time.Time             `json:"createdAt"`
+	CompletedAt    *time.Time             `json:"completedAt"`
+	ObjectCount    uint64                 `json:"objectCount,string"`
+	FileSize       uint64                 `json:"fileSize,string"`
+	Url            string                 `json:"url"`
+	PartialDataUrl string                 `json:"partialDataUrl"`
+	Query          string                 `json:"query"`
+}
+
+// Done reports whether the operation stopped running, successfully or not.
+func (o *BulkOperation) Done() bool {
+	switch o.Status {
+	case BulkOperationStatusCompleted, BulkOperationStatusCanceled,
+		BulkOperationStatusFailed, BulkOperationStatusExpired:
+		return true
+	}
+	return false
+}
+
+// BulkOperationError is returned when a bulk operation failed, was canceled or
+// expired. Failed operations may have partial results, see
+// BulkOperation.PartialDataUrl.
+type BulkOperationError struct {
+	Operation *BulkOperation
+}
+
+func (e BulkOperationError) Error() string {
+	op := e.Operation
+	if op.Status == BulkOperationStatusFailed && op.ErrorCode != "" {
+		return fmt.Sprintf("bulk operation %s failed: %s", op.Id, op.ErrorCode)
+	}
+	return fmt.Sprintf("bulk operation %s %s", op.Id, strings.ToLower(string(op.Status)))
+}
+
+const bulkOperationFields = `id type status errorCode createdAt completedAt objectCount fileSize url partialDataUrl query`
+
+const (
+	defaultBulkPollInterval = time.Second
+	maxBulkPollInterval     = 30 * time.Second
+)
+
+// bulkOperationPayload is the payload of the bulk operation mutations, their
+// userErrors are returned by Mutate
+type bulkOperationPayload struct {
+	BulkOperation *BulkOperation `json:"bulkOperation"`
+}
+
+// bulkOperationResult returns the operation of a bulk operation mutation
+// along with the userErrors returned by Mutate, other errors without it.
+func bulkOperationResult(payload bulkOperationPayload, err error) (*BulkOperation, error) {
+	var userErrors GraphQLUserErrors
+	if err != nil && !errors.As(err, &userErrors) {
+		return nil, err
+	}
+	return payload.BulkOperation, err
+}
+
+// RunQuery starts a bulk operation exporting the results of query, which
+// returns once the operation was created. Only one bulk query operation runs
+// at a time per shop, userErrors are returned as GraphQLUserErrors.
+func (s *BulkOperationServiceOp) RunQuery(ctx context.Context, query string) (*BulkOperation, error) {
+	q := `mutation bulkOperationRunQuery($query: String!) {
+		bulkOperationRunQuery(query: $query) {
+			bulkOperation { ` + bulkOperationFields + ` }
+			userErrors { field message }
+		}
+	}`
+
+	resp := struct {
+		BulkOperationRunQuery bulkOperationPayload `json:"bulkOperationRunQuery"`
+	}{}
+	vars := map[string]interface{}{"query": query}
+	err := s.client.GraphQL.Mutate(ctx, q, vars, &resp)
+	return bulkOperationResult(resp.BulkOperationRunQuery, err)
+}
+
+// Current returns the most recent bulk operation of the type, or nil when the
+// shop never ran one.
+func (s *BulkOperationServiceOp) Current(ctx context.Context, operationType BulkOperationType) (*BulkOperation, error) {
+	if operationType == "" {
+		operationType = BulkOperationTypeQuery
+	}
+
+	q := `query currentBulkOperation($type: BulkOperationType!) {
+		currentBulkOperation(type: $type) { ` + bulkOperationFields + ` }
+	}`
+
+	resp := struct {
+		CurrentBulkOperation *BulkOperation `json:"currentBulkOperation"`
+	}{}
+	vars := map[string]interface{}{"type": operationType}
+	if err := s.client.GraphQL.Query(ctx, q, vars, &resp); err != nil {
+		return nil, err
+	}
+	return resp.CurrentBulkOperation, nil
+}
+
+// Cancel requests the cancellation of a running bulk operation, which is
+// CANCELING until Shopify stopped it.
+func (s *BulkOperationServiceOp) Cancel(ctx context.Context, id string) (*BulkOperation, error) {
+	q := `mutation bulkOperationCancel($id: ID!) {
+		bulkOperationCancel(id: $id) {
+			bulkOperation { ` + bulkOperationFields + ` }
+			userErrors { field message }
+		}
+	}`
+
+	resp := struct {
+		BulkOperationCancel bulkOperationPayload `json:"bulkOperationCancel"`
+	}{}
+	vars := map[string]interface{}{"id": id}
+	err := s.client.GraphQL.Mutate(ctx, q, vars, &resp)
+	return bulkOperationResult(resp.BulkOperationCancel, err)
+}
+
+// Wait polls the current bulk operation until the operation is done, backing
+// off from one to 30 seconds between polls. It returns the completed
+// operation, or a BulkOperationError along with the operation when it failed,
+// was canceled or expired. The operation keeps running when ctx is done, see
+// RunBulkQuery to cancel it.
+func (s *BulkOperationServiceOp) Wait(ctx context.Context, operation *BulkOperation) (*BulkOperation, error) {
+	if operation == nil {
+		return nil, fmt.Errorf("no bulk operation to wait for")
+	}
+
+	interval := s.client.bulkPollInterval
+	if interval <= 0 {
+		interval = defaultBulkPollInterval
+	}
+
+	for {
+		current, err := s.Current(ctx, operation.Type)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil || current.Id != operation.Id {
+			return nil, fmt.Errorf("bulk operation %s is no longer the current operation", operation.Id)
+		}
+
+		if current.Done() {
+			if current.Status != BulkOperationStatusCompleted {
+				return current, BulkOperationError{Operation: current}
+			}
+			return current, nil
+		}
+
+		s.client.log.Debugf("bulk operation %s is %s, waiting %s", current.Id, current.Status, interval)
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+
+		interval = interval * 3 / 2
+		if interval > maxBulkPollInterval {
+			interval = maxBulkPollInterval
+		}
+	}
+}
+
+// Download returns the JSONL results of a completed operation, or the partial
+// results of a failed one, see ReadBulkOperationResults. The results are empty
+// when the operation found no objects. The caller must close the results.
+func (s *BulkOperationServiceOp) Download(ctx context.Context, operation *BulkOperation) (io.ReadCloser, error) {
+	url := operation.Url
+	if url == "" {
+		url = operation.PartialDataUrl
+	}
+	if url == "" {
+		return ioutil.NopCloser(strings.NewReader("")), nil
+	}
+
+	// the url is signed, the shop's access token must not be sent along
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, ResponseError{
+			Status:  resp.StatusCode,
+			Message: fmt.Sprintf("downloading the results of bulk operation %s: %s", operation.Id, resp.Status),
+		}
+	}
+	return resp.Body, nil
+}
+
+// RunBulkQuery runs a bulk query, waits for it and calls fn with every root
+// object of its results, see ReadBulkOperationResults:
+//
+//	query := `{ products { edges { node { id title variants { edges { node { id sku } } } } } } }`
+//	err := goshopify.RunBulkQuery(ctx, client.BulkOperation, query, func(p BulkProduct) error {
+//		return write(p)
+//	})
+//
+// The operation is canceled when ctx is done while it is running.
+func RunBulkQuery[T any](ctx context.Context, service BulkOperationService, query string, fn func(T) error) error {
+	operation, err := service.RunQuery(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	results, err := awaitBulkResults(ctx, service, operation)
+	if err != nil {
+		return err
+	}
+	defer results.Close()
+
+	return ReadBulkOperationResults(results, fn)
+}
+
+// awaitBulkResults waits for a bulk operation and downloads its results, the
+// operation is canceled when ctx is done while it runs
+func awaitBulkResults(ctx context.Context, service BulkOperationService, operation *BulkOperation) (io.ReadCloser, error) {
+	completed, err := service.Wait(ctx, operation)
+	if err != nil {
+		if ctx.Err() != nil {
+			// ctx is done, cancel the operation with a context of its own
+			cancelCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			_, _ = service.Cancel(cancelCtx, operation.Id)
+		}
+		return nil, err
+	}
+
+	return service.Download(ctx, completed)
+}
+
+// bulkNode is a line of the results of a bulk operation along with the lines
+// nested under it
+type bulkNode struct {
+	raw      json.RawMessage
+	id       string
+	parentId string
+	typename string
+	children []*bulkNode
+}
+
+// ReadBulkOperationResults streams the JSONL results of a bulk query and calls
+// fn with every root object decoded into a T, a struct or a pointer to one.
+//
+// Bulk operations flatten nested connections: every node of a connection is a
+// line of its own, following its parent, with the parent's id in __parentId.
+// The nodes are appended to the slice fields of their parent struct tagged
+// with their resource type, e.g. "ProductVariant" for the nodes of
+// gid://shopify/ProductVariant/1. Nodes without an id must select __typename.
+//
+//	type BulkProduct struct {
+//		Id       string        `json:"id"`
+//		Title    string        `json:"title"`
+//		Variants []BulkVariant `bulk:"ProductVariant"`
+//	}
+//
+// Nodes without a matching field are ignored. The lines of a root object are
+// held in memory until the next root object is read.
+func ReadBulkOperationResults[T any](r io.Reader, fn func(T) error) error {
+	decoder := json.NewDecoder(r)
+
+	var root *bulkNode
+	nodes := make(map[string]*bulkNode)
+
+	emit := func() error {
+		if root == nil {
+			return nil
+		}
+		var v T
+		if err := decodeBulkNode(reflect.ValueOf(&v).Elem(), root); err != nil {
+			return err
+		}
+		return fn(v)
+	}
+
+	for {
+		node := &bulkNode{}
+		if err := decoder.Decode(&node.raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		line := struct {
+			Id       string `json:"id"`
+			ParentId string `json:"__parentId"`
+			Typename string `json:"__typename"`
+		}{}
+		if err := json.Unmarshal(node.raw, &line); err != nil {
+			return err
+		}
+		node.id, node.parentId, node.typename = line.Id, line.ParentId, line.Typename
+
+		if node.parentId == "" {
+			if err := emit(); err != nil {
+				return err
+			}
+			root = node
+			nodes = map[string]*bulkNode{}
+		} else {
+			parent, ok := nodes[node.parentId]
+			if !ok {
+				return fmt.Errorf("bulk operation results: parent %s of %s not found", node.parentId, node.id)
+			}
+			parent.children = append(parent.children, node)
+		}
+
+		if node.id != "" {
+			nodes[node.id] = node
+		}
+	}
+
+	return emit()
+}
+
+// decodeBulkNode decodes a node into v and appends its children to the fields
+// of v tagged with their resource type
+func decodeBulkNode(v reflect.Value, node *bulkNode) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if err := json.Unmarshal(node.raw, v.Addr().Interface()); err != nil {
+		return err
+	}
+	if len(node.children) == 0 {
+		return nil
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("bulk operation results: cannot nest the children of %s in %s", node.id, v.Type())
+	}
+
+	for _, child := range node.children {
+		typename := child.resourceType()
+		if typename == "" {
+			return fmt.Errorf("bulk operation results: child of %s has neither an id nor a __typename", node.id)
+		}
+
+		field, ok := bulkField(v, typename)
+		if !ok {
+			continue
+		}
+
+		elem := reflect.New(field.Type().Elem()).Elem()
+		if err := decodeBulkNode(elem, child); err != nil {
+			return err
+		}
+		field.Set(reflect.Append(field, elem))
+	}
+	return nil
+}
+
+// resourceType returns the __typename of the node, or the resource type of its
+// id, e.g. "Product" for gid://shopify/Product/1
+func (n *bulkNode) resourceType() string {
+	if n.typename != "" {
+		return n.typename
+	}
+	const prefix = "gid://shopify/"
+	if !strings.HasPrefix(n.id, prefix) {
+		return ""
+	}
+	typename := strings.TrimPrefix(n.id, prefix)
+	if i := strings.IndexByte(typename, '/'); i >= 0 {
+		typename = typename[:i]
+	}
+	return typename
+}
+
+// bulkField returns the slice field of a struct tagged with a resource type
+func bulkField(v reflect.Value, typename string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("bulk") != typename || field.Type.Kind() != reflect.Slice {
+			continue
+		}
+		return v.Field(i), true
+	}
+	return reflect.Value{}, false
+}
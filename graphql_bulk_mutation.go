@@ -0,0 +1,257 @@
+package goshopify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+)
+
+// stagedUploadTarget is where a file is uploaded to before a mutation refers
+// to it
+type stagedUploadTarget struct {
+	Url         string `json:"url"`
+	ResourceUrl string `json:"resourceUrl"`
+	Parameters  []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"parameters"`
+}
+
+// maxUploadErrorBody is how much of a failed upload's response is kept in the
+// error
+const maxUploadErrorBody = 1024
+
+// UploadVariables uploads the JSONL variables of a bulk mutation, one object
+// of variables per line, and returns the staged upload path to run the
+// mutation with. Shopify limits the file to 20MB.
+func (s *BulkOperationServiceOp) UploadVariables(ctx context.Context, variables io.Reader) (string, error) {
+	q := `mutation stagedUploadsCreate($input: [StagedUploadInput!]!) {
+		stagedUploadsCreate(input: $input) {
+			stagedTargets { url resourceUrl parameters { name value } }
+			userErrors { field message }
+		}
+	}`
+
+	resp := struct {
+		StagedUploadsCreate struct {
+			StagedTargets []stagedUploadTarget `json:"stagedTargets"`
+		} `json:"stagedUploadsCreate"`
+	}{}
+	vars := map[string]interface{}{
+		"input": []map[string]string{{
+			"resource":   "BULK_MUTATION_VARIABLES",
+			"filename":   "bulk_mutation_variables.jsonl",
+			"mimeType":   "text/jsonl",
+			"httpMethod": http.MethodPost,
+		}},
+	}
+	if err := s.client.GraphQL.Mutate(ctx, q, vars, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.StagedUploadsCreate.StagedTargets) == 0 {
+		return "", fmt.Errorf("stagedUploadsCreate returned no staged target")
+	}
+
+	target := resp.StagedUploadsCreate.StagedTargets[0]
+	if err := s.upload(ctx, target, variables); err != nil {
+		return "", err
+	}
+
+	// the mutation refers to the upload by its key
+	for _, param := range target.Parameters {
+		if param.Name == "key" {
+			return param.Value, nil
+		}
+	}
+	return "", fmt.Errorf("staged target %s has no key parameter", target.Url)
+}
+
+// upload posts a file to a staged target as a multipart form of the target's
+// parameters followed by the file
+func (s *BulkOperationServiceOp) upload(ctx context.Context, target stagedUploadTarget, file io.Reader) error {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	for _, param := range target.Parameters {
+		if err := form.WriteField(param.Name, param.Value); err != nil {
+			return err
+		}
+	}
+	part, err := form.CreateFormFile("file", "bulk_mutation_variables.jsonl")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	// the target is signed, the shop's access token must not be sent along
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.Url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := s.client.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxUploadErrorBody))
+		return ResponseError{
+			Status:  resp.StatusCode,
+			Message: fmt.Sprintf("uploading bulk mutation variables: %s %s", resp.Status, bytes.TrimSpace(message)),
+		}
+	}
+	return nil
+}
+
+// RunMutation starts a bulk operation running mutation with every line of the
+// variables uploaded to stagedUploadPath, see UploadVariables. Only one bulk
+// mutation operation runs at a time per shop, userErrors are returned as
+// GraphQLUserErrors.
+func (s *BulkOperationServiceOp) RunMutation(ctx context.Context, mutation, stagedUploadPath string) (*BulkOperation, error) {
+	q := `mutation bulkOperationRunMutation($mutation: String!, $stagedUploadPath: String!) {
+		bulkOperationRunMutation(mutation: $mutation, stagedUploadPath: $stagedUploadPath) {
+			bulkOperation { ` + bulkOperationFields + ` }
+			userErrors { field message }
+		}
+	}`
+
+	resp := struct {
+		BulkOperationRunMutation bulkOperationPayload `json:"bulkOperationRunMutation"`
+	}{}
+	vars := map[string]interface{}{
+		"mutation":         mutation,
+		"stagedUploadPath": stagedUploadPath,
+	}
+	err := s.client.GraphQL.Mutate(ctx, q, vars, &resp)
+	return bulkOperationResult(resp.BulkOperationRunMutation, err)
+}
+
+// BulkMutationResult is the result of the mutation run with one line of the
+// variables of a bulk mutation.
+type BulkMutationResult[R any] struct {
+	// Line is the index of the line of variables, starting at 0.
+	Line int
+
+	// Payload is the payload returned by the mutation, e.g. the
+	// ProductVariantUpdatePayload of productVariantUpdate.
+	Payload R
+
+	// UserErrors are the userErrors of the payload.
+	UserErrors GraphQLUserErrors
+
+	// Err holds the errors of the line, such as invalid variables, as a
+	// ResponseError.
+	Err error
+}
+
+// Success reports whether the mutation succeeded for the line.
+func (r BulkMutationResult[R]) Success() bool {
+	return r.Err == nil && len(r.UserErrors) == 0
+}
+
+// ReadBulkMutationResults streams the JSONL results of a bulk mutation and
+// calls fn with the result of every line of variables. The lines are not
+// necessarily in the order of the variables.
+func ReadBulkMutationResults[R any](r io.Reader, fn func(BulkMutationResult[R]) error) error {
+	decoder := json.NewDecoder(r)
+
+	for {
+		line := struct {
+			Data       map[string]json.RawMessage `json:"data"`
+			Errors     []GraphQLError             `json:"errors"`
+			LineNumber int                        `json:"__lineNumber"`
+		}{}
+		if err := decoder.Decode(&line); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		result := BulkMutationResult[R]{Line: line.LineNumber}
+		if len(line.Errors) > 0 {
+			responseError := ResponseError{Status: http.StatusOK, GraphQLErrors: line.Errors}
+			for _, err := range line.Errors {
+				responseError.Errors = append(responseError.Errors, err.Message)
+			}
+			result.Err = responseError
+		}
+
+		// data holds the payload of the one mutation
+		for _, payload := range line.Data {
+			if len(payload) == 0 || string(payload) == "null" {
+				continue
+			}
+
+			userErrors := struct {
+				UserErrors GraphQLUserErrors `json:"userErrors"`
+			}{}
+			if err := json.Unmarshal(payload, &userErrors); err != nil {
+				return err
+			}
+			result.UserErrors = userErrors.UserErrors
+
+			if err := json.Unmarshal(payload, &result.Payload); err != nil {
+				return err
+			}
+		}
+
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+}
+
+// RunBulkMutation uploads variables, runs mutation with each of them in a bulk
+// operation, waits for it and calls fn with the result of every variables:
+//
+//	mutation := `mutation call($input: ProductVariantInput!) {
+//		productVariantUpdate(input: $input) { productVariant { id } userErrors { field message } }
+//	}`
+//	err := goshopify.RunBulkMutation(ctx, client.BulkOperation, mutation, variables,
+//		func(result goshopify.BulkMutationResult[VariantUpdatePayload]) error {
+//			if !result.Success() {
+//				log.Printf("variant %v: %v", variables[result.Line], result.UserErrors)
+//			}
+//			return nil
+//		})
+//
+// The operation is canceled when ctx is done while it is running.
+func RunBulkMutation[V, R any](ctx context.Context, service BulkOperationService, mutation string, variables []V, fn func(BulkMutationResult[R]) error) error {
+	jsonl := &bytes.Buffer{}
+	encoder := json.NewEncoder(jsonl)
+	for _, v := range variables {
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+	}
+
+	path, err := service.UploadVariables(ctx, jsonl)
+	if err != nil {
+		return err
+	}
+
+	operation, err := service.RunMutation(ctx, mutation, path)
+	if err != nil {
+		return err
+	}
+
+	results, err := awaitBulkResults(ctx, service, operation)
+	if err != nil {
+		return err
+	}
+	defer results.Close()
+
+	return ReadBulkMutationResults(results, fn)
+}
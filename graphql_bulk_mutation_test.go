@@ -0,0 +1,166 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+type bulkVariantPayload struct {
+	ProductVariant *struct {
+		Id string `json:"id"`
+	} `json:"productVariant"`
+}
+
+func TestRunBulkMutation(t *testing.T) {
+	setup()
+	defer teardown()
+	client.bulkPollInterval = time.Millisecond
+
+	var uploaded, key string
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprintln(w, `{"data":{"productVariantUpdate":{"productVariant":null,"userErrors":[{"field":["input","price"],"message":"Price is invalid"}]}},"__lineNumber":1}`)
+			fmt.Fprintln(w, `{"data":{"productVariantUpdate":{"productVariant":{"id":"gid://shopify/ProductVariant/1"},"userErrors":[]}},"__lineNumber":0}`)
+			fmt.Fprintln(w, `{"errors":[{"message":"Variable $input of type ProductVariantInput! was provided invalid value"}],"__lineNumber":2}`)
+			return
+		}
+
+		if r.Header.Get("X-Shopify-Access-Token") != "" {
+			t.Error("the access token was sent along with the upload")
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("upload has no file: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		b, _ := ioutil.ReadAll(file)
+		uploaded = string(b)
+		key = r.FormValue("key")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer storage.Close()
+	httpmock.RegisterNoResponder(httpmock.InitialTransport.RoundTrip)
+
+	var stagedUploadPath, mutation interface{}
+	registerGraphQL(func(operation string, vars map[string]interface{}) string {
+		switch operation {
+		case "stagedUploadsCreate":
+			return `{"stagedUploadsCreate":{"stagedTargets":[{"url":"` + storage.URL + `","parameters":[{"name":"key","value":"tmp/1/bulk/vars.jsonl"},{"name":"policy","value":"abc"}]}],"userErrors":[]}}`
+		case "bulkOperationRunMutation":
+			stagedUploadPath = vars["stagedUploadPath"]
+			mutation = vars["mutation"]
+			return `{"bulkOperationRunMutation":{"bulkOperation":{"id":"gid://shopify/BulkOperation/2","type":"MUTATION","status":"CREATED"},"userErrors":[]}}`
+		}
+		if vars["type"] != "MUTATION" {
+			t.Errorf("currentBulkOperation type = %v, expected MUTATION", vars["type"])
+		}
+		return `{"currentBulkOperation":{"id":"gid://shopify/BulkOperation/2","type":"MUTATION","status":"COMPLETED","url":"` + storage.URL + `"}}`
+	})
+
+	type input struct {
+		Id    string `json:"id"`
+		Price string `json:"price"`
+	}
+	variables := []map[string]input{
+		{"input": {Id: "gid://shopify/ProductVariant/1", Price: "10.00"}},
+		{"input": {Id: "gid://shopify/ProductVariant/2", Price: "-1"}},
+		{"input": {}},
+	}
+
+	results := make(map[int]BulkMutationResult[bulkVariantPayload])
+	err := RunBulkMutation(context.Background(), client.BulkOperation, "mutation call($input: ProductVariantInput!) { ... }", variables,
+		func(result BulkMutationResult[bulkVariantPayload]) error {
+			results[result.Line] = result
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("RunBulkMutation returned error: %v", err)
+	}
+
+	expectedUpload := `{"input":{"id":"gid://shopify/ProductVariant/1","price":"10.00"}}
+{"input":{"id":"gid://shopify/ProductVariant/2","price":"-1"}}
+{"input":{"id":"","price":""}}
+`
+	if uploaded != expectedUpload {
+		t.Errorf("uploaded %q, expected %q", uploaded, expectedUpload)
+	}
+	if key != "tmp/1/bulk/vars.jsonl" || stagedUploadPath != key {
+		t.Errorf("uploaded key %q and ran the mutation with %v", key, stagedUploadPath)
+	}
+	if mutation != "mutation call($input: ProductVariantInput!) { ... }" {
+		t.Errorf("ran mutation %v", mutation)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("RunBulkMutation emitted %d results, expected 3", len(results))
+	}
+	if !results[0].Success() || results[0].Payload.ProductVariant.Id != "gid://shopify/ProductVariant/1" {
+		t.Errorf("result of line 0 = %+v", results[0])
+	}
+	expectedUserErrors := GraphQLUserErrors{{Field: []string{"input", "price"}, Message: "Price is invalid"}}
+	if results[1].Success() || !reflect.DeepEqual(results[1].UserErrors, expectedUserErrors) {
+		t.Errorf("result of line 1 = %+v", results[1])
+	}
+	if results[2].Success() || results[2].Err == nil || !strings.Contains(results[2].Err.Error(), "invalid value") {
+		t.Errorf("result of line 2 = %+v", results[2])
+	}
+}
+
+func TestBulkOperationUploadVariablesErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "<Error><Code>AccessDenied</Code></Error>")
+	}))
+	defer storage.Close()
+	httpmock.RegisterNoResponder(httpmock.InitialTransport.RoundTrip)
+
+	cases := []struct {
+		response string
+		expected string
+	}{
+		{
+			`{"stagedTargets":[],"userErrors":[{"field":["input"],"message":"Resource is invalid"}]}`,
+			"input: Resource is invalid",
+		},
+		{
+			`{"stagedTargets":[{"url":"` + storage.URL + `","parameters":[{"name":"key","value":"tmp/1"}]}],"userErrors":[]}`,
+			"uploading bulk mutation variables: 403 Forbidden <Error><Code>AccessDenied</Code></Error>",
+		},
+	}
+
+	for _, c := range cases {
+		registerGraphQL(func(string, map[string]interface{}) string {
+			return `{"stagedUploadsCreate":` + c.response + `}`
+		})
+
+		_, err := client.BulkOperation.UploadVariables(context.Background(), strings.NewReader("{}\n"))
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("BulkOperation.UploadVariables returned %v, expected %s", err, c.expected)
+		}
+	}
+}
+
+func TestReadBulkMutationResultsHandlerError(t *testing.T) {
+	failure := errors.New("database down")
+	err := ReadBulkMutationResults(strings.NewReader(`{"data":{"productDelete":null},"__lineNumber":0}`),
+		func(BulkMutationResult[bulkVariantPayload]) error {
+			return failure
+		})
+	if err != failure {
+		t.Errorf("ReadBulkMutationResults returned %v, expected %v", err, failure)
+	}
+}
@@ -0,0 +1,336 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// registerGraphQL answers graphql queries with the data returned by respond
+// for the name of the query
+func registerGraphQL(respond func(operation string, vars map[string]interface{}) string) {
+	httpmock.RegisterResponder(
+		"POST",
+		fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			body := struct {
+				Query     string                 `json:"query"`
+				Variables map[string]interface{} `json:"variables"`
+			}{}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			data := respond(graphQLOperationName(body.Query), body.Variables)
+			return httpmock.NewStringResponse(200, `{"data":`+data+`}`), nil
+		},
+	)
+}
+
+func TestBulkOperationRunQuery(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var query interface{}
+	registerGraphQL(func(operation string, vars map[string]interface{}) string {
+		query = vars["query"]
+		return `{"bulkOperationRunQuery":{"bulkOperation":{"id":"gid://shopify/BulkOperation/1","type":"QUERY","status":"CREATED","objectCount":"0","fileSize":null,"url":null},"userErrors":[]}}`
+	})
+
+	operation, err := client.BulkOperation.RunQuery(context.Background(), "{ products { edges { node { id } } } }")
+	if err != nil {
+		t.Fatalf("BulkOperation.RunQuery returned error: %v", err)
+	}
+
+	expected := &BulkOperation{Id: "gid://shopify/BulkOperation/1", Type: BulkOperationTypeQuery, Status: BulkOperationStatusCreated}
+	if !reflect.DeepEqual(operation, expected) {
+		t.Errorf("BulkOperation.RunQuery returned %#v, expected %#v", operation, expected)
+	}
+	if query != "{ products { edges { node { id } } } }" {
+		t.Errorf("BulkOperation.RunQuery sent query %v", query)
+	}
+}
+
+func TestBulkOperationRunQueryUserErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	registerGraphQL(func(string, map[string]interface{}) string {
+		return `{"bulkOperationRunQuery":{"bulkOperation":null,"userErrors":[{"field":["query"],"message":"Invalid bulk query"}]}}`
+	})
+
+	_, err := client.BulkOperation.RunQuery(context.Background(), "{ shop { name } }")
+
+	var userErrors GraphQLUserErrors
+	if !errors.As(err, &userErrors) {
+		t.Fatalf("BulkOperation.RunQuery returned %v, expected GraphQLUserErrors", err)
+	}
+	if err.Error() != "query: Invalid bulk query" {
+		t.Errorf("BulkOperation.RunQuery returned error message %q", err.Error())
+	}
+}
+
+func TestBulkOperationWait(t *testing.T) {
+	setup()
+	defer teardown()
+	client.bulkPollInterval = time.Millisecond
+
+	polls := 0
+	registerGraphQL(func(operation string, vars map[string]interface{}) string {
+		polls++
+		if vars["type"] != "QUERY" {
+			t.Errorf("currentBulkOperation type = %v, expected QUERY", vars["type"])
+		}
+		if polls < 3 {
+			return `{"currentBulkOperation":{"id":"gid://shopify/BulkOperation/1","status":"RUNNING","objectCount":"12"}}`
+		}
+		return `{"currentBulkOperation":{"id":"gid://shopify/BulkOperation/1","status":"COMPLETED","objectCount":"42","fileSize":"1024","url":"https://storage.example.com/1.jsonl"}}`
+	})
+
+	operation, err := client.BulkOperation.Wait(context.Background(), &BulkOperation{Id: "gid://shopify/BulkOperation/1", Type: BulkOperationTypeQuery})
+	if err != nil {
+		t.Fatalf("BulkOperation.Wait returned error: %v", err)
+	}
+	if polls != 3 {
+		t.Errorf("BulkOperation.Wait polled %d times, expected 3", polls)
+	}
+	if operation.ObjectCount != 42 || operation.FileSize != 1024 || operation.Url != "https://storage.example.com/1.jsonl" {
+		t.Errorf("BulkOperation.Wait returned %#v", operation)
+	}
+}
+
+func TestBulkOperationWaitFailed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	cases := []struct {
+		response string
+		expected string
+	}{
+		{`{"id":"gid://shopify/BulkOperation/1","status":"FAILED","errorCode":"ACCESS_DENIED"}`, "bulk operation gid://shopify/BulkOperation/1 failed: ACCESS_DENIED"},
+		{`{"id":"gid://shopify/BulkOperation/1","status":"CANCELED"}`, "bulk operation gid://shopify/BulkOperation/1 canceled"},
+		{`{"id":"gid://shopify/BulkOperation/2","status":"RUNNING"}`, "bulk operation gid://shopify/BulkOperation/1 is no longer the current operation"},
+	}
+
+	for _, c := range cases {
+		registerGraphQL(func(string, map[string]interface{}) string {
+			return `{"currentBulkOperation":` + c.response + `}`
+		})
+
+		_, err := client.BulkOperation.Wait(context.Background(), &BulkOperation{Id: "gid://shopify/BulkOperation/1"})
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("BulkOperation.Wait returned %v, expected %s", err, c.expected)
+		}
+	}
+
+	if _, err := client.BulkOperation.Wait(context.Background(), nil); err == nil {
+		t.Error("BulkOperation.Wait without an operation should return an error")
+	}
+}
+
+func TestRunBulkQuery(t *testing.T) {
+	setup()
+	defer teardown()
+	client.bulkPollInterval = time.Millisecond
+
+	results := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Shopify-Access-Token") != "" {
+			t.Error("the access token was sent along with the download")
+		}
+		fmt.Fprintln(w, `{"id":"gid://shopify/Product/1","title":"Shirt"}`)
+		fmt.Fprintln(w, `{"id":"gid://shopify/ProductVariant/11","sku":"S","__parentId":"gid://shopify/Product/1"}`)
+		fmt.Fprintln(w, `{"id":"gid://shopify/ProductVariant/12","sku":"M","__parentId":"gid://shopify/Product/1"}`)
+		fmt.Fprintln(w, `{"id":"gid://shopify/Product/2","title":"Hat"}`)
+	}))
+	defer results.Close()
+	httpmock.RegisterNoResponder(httpmock.InitialTransport.RoundTrip)
+
+	registerGraphQL(func(operation string, vars map[string]interface{}) string {
+		if operation == "bulkOperationRunQuery" {
+			return `{"bulkOperationRunQuery":{"bulkOperation":{"id":"gid://shopify/BulkOperation/1","type":"QUERY","status":"CREATED"},"userErrors":[]}}`
+		}
+		return `{"currentBulkOperation":{"id":"gid://shopify/BulkOperation/1","type":"QUERY","status":"COMPLETED","url":"` + results.URL + `"}}`
+	})
+
+	type variant struct {
+		Id  string `json:"id"`
+		Sku string `json:"sku"`
+	}
+	type product struct {
+		Id       string    `json:"id"`
+		Title    string    `json:"title"`
+		Variants []variant `bulk:"ProductVariant"`
+	}
+
+	var products []product
+	err := RunBulkQuery(context.Background(), client.BulkOperation, "{ products { edges { node { id } } } }", func(p product) error {
+		products = append(products, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunBulkQuery returned error: %v", err)
+	}
+
+	expected := []product{
+		{Id: "gid://shopify/Product/1", Title: "Shirt", Variants: []variant{
+			{Id: "gid://shopify/ProductVariant/11", Sku: "S"},
+			{Id: "gid://shopify/ProductVariant/12", Sku: "M"},
+		}},
+		{Id: "gid://shopify/Product/2", Title: "Hat"},
+	}
+	if !reflect.DeepEqual(products, expected) {
+		t.Errorf("RunBulkQuery emitted %+v, expected %+v", products, expected)
+	}
+}
+
+func TestRunBulkQueryCanceled(t *testing.T) {
+	setup()
+	defer teardown()
+	client.bulkPollInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var canceled interface{}
+	registerGraphQL(func(operation string, vars map[string]interface{}) string {
+		switch operation {
+		case "bulkOperationRunQuery":
+			return `{"bulkOperationRunQuery":{"bulkOperation":{"id":"gid://shopify/BulkOperation/1","status":"CREATED"},"userErrors":[]}}`
+		case "bulkOperationCancel":
+			canceled = vars["id"]
+			return `{"bulkOperationCancel":{"bulkOperation":{"id":"gid://shopify/BulkOperation/1","status":"CANCELING"},"userErrors":[]}}`
+		}
+		cancel()
+		return `{"currentBulkOperation":{"id":"gid://shopify/BulkOperation/1","status":"RUNNING"}}`
+	})
+
+	err := RunBulkQuery(ctx, client.BulkOperation, "{ products { edges { node { id } } } }", func(interface{}) error {
+		t.Error("RunBulkQuery emitted results of a canceled operation")
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("RunBulkQuery returned %v, expected context.Canceled", err)
+	}
+	if canceled != "gid://shopify/BulkOperation/1" {
+		t.Errorf("bulkOperationCancel was called with %v", canceled)
+	}
+}
+
+func TestBulkOperationDownloadEmpty(t *testing.T) {
+	setup()
+	defer teardown()
+
+	results, err := client.BulkOperation.Download(context.Background(), &BulkOperation{Status: BulkOperationStatusCompleted})
+	if err != nil {
+		t.Fatalf("BulkOperation.Download returned error: %v", err)
+	}
+	defer results.Close()
+
+	err = ReadBulkOperationResults(results, func(interface{}) error {
+		t.Error("results of an empty operation were read")
+		return nil
+	})
+	if err != nil {
+		t.Errorf("ReadBulkOperationResults returned error: %v", err)
+	}
+}
+
+func TestReadBulkOperationResults(t *testing.T) {
+	type lineItem struct {
+		Sku string `json:"sku"`
+	}
+	type metafield struct {
+		Key string `json:"key"`
+	}
+	type order struct {
+		Id         string      `json:"id"`
+		Name       string      `json:"name"`
+		LineItems  []*lineItem `bulk:"LineItem"`
+		Metafields []metafield `bulk:"Metafield"`
+	}
+	type customer struct {
+		Id     string  `json:"id"`
+		Orders []order `bulk:"Order"`
+	}
+
+	jsonl := `{"id":"gid://shopify/Customer/1"}
+{"id":"gid://shopify/Order/10","name":"#1001","__parentId":"gid://shopify/Customer/1"}
+{"sku":"A","__typename":"LineItem","__parentId":"gid://shopify/Order/10"}
+{"id":"gid://shopify/Metafield/7","key":"gift","__parentId":"gid://shopify/Order/10"}
+{"id":"gid://shopify/Order/11","name":"#1002","__parentId":"gid://shopify/Customer/1"}
+{"id":"gid://shopify/MailingAddress/3","__parentId":"gid://shopify/Customer/1"}
+{"id":"gid://shopify/Customer/2"}
+`
+
+	var customers []*customer
+	err := ReadBulkOperationResults(strings.NewReader(jsonl), func(c *customer) error {
+		customers = append(customers, c)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadBulkOperationResults returned error: %v", err)
+	}
+
+	expected := []*customer{
+		{Id: "gid://shopify/Customer/1", Orders: []order{
+			{
+				Id:         "gid://shopify/Order/10",
+				Name:       "#1001",
+				LineItems:  []*lineItem{{Sku: "A"}},
+				Metafields: []metafield{{Key: "gift"}},
+			},
+			{Id: "gid://shopify/Order/11", Name: "#1002"},
+		}},
+		{Id: "gid://shopify/Customer/2"},
+	}
+	if !reflect.DeepEqual(customers, expected) {
+		t.Errorf("ReadBulkOperationResults emitted %+v, expected %+v", customers, expected)
+	}
+}
+
+func TestReadBulkOperationResultsErrors(t *testing.T) {
+	type product struct {
+		Id string `json:"id"`
+	}
+
+	cases := []struct {
+		jsonl    string
+		expected string
+	}{
+		{
+			`{"id":"gid://shopify/Product/1"}` + "\n" + `{"id":"gid://shopify/ProductVariant/2","__parentId":"gid://shopify/Product/9"}`,
+			"bulk operation results: parent gid://shopify/Product/9 of gid://shopify/ProductVariant/2 not found",
+		},
+		{
+			`{"id":"gid://shopify/Product/1"}` + "\n" + `{"sku":"A","__parentId":"gid://shopify/Product/1"}`,
+			"bulk operation results: child of gid://shopify/Product/1 has neither an id nor a __typename",
+		},
+		{
+			`{"id":"gid://shopify/Product/1"`,
+			"unexpected EOF",
+		},
+	}
+
+	for _, c := range cases {
+		err := ReadBulkOperationResults(strings.NewReader(c.jsonl), func(product) error { return nil })
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("ReadBulkOperationResults returned %v, expected %s", err, c.expected)
+		}
+	}
+
+	failure := errors.New("disk full")
+	err := ReadBulkOperationResults(strings.NewReader(`{"id":"gid://shopify/Product/1"}`), func(product) error {
+		return failure
+	})
+	if err != failure {
+		t.Errorf("ReadBulkOperationResults returned %v, expected %v", err, failure)
+	}
+}
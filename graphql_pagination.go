@@ -0,0 +1,125 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// graphQLConnection is a page of a connection, with its nodes either as
+// nodes or as edges
+type graphQLConnection struct {
+	Nodes []json.RawMessage `json:"nodes"`
+	Edges []struct {
+		Node json.RawMessage `json:"node"`
+	} `json:"edges"`
+	PageInfo *struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+}
+
+// QueryConnection runs a query selecting a connection page after page and
+// calls fn with every node of the connection. The cursor of the next page is
+// passed to the query in the cursorVar variable, and path is the dot separated
+// path of the connection in the response, e.g. "product.variants". The
+// connection must select pageInfo { hasNextPage endCursor } and either nodes
+// or edges { node }:
+//
+//	q := `query products($first: Int!, $after: String) {
+//		products(first: $first, after: $after) {
+//			nodes { id title }
+//			pageInfo { hasNextPage endCursor }
+//		}
+//	}`
+//	err := client.GraphQL.QueryConnection(ctx, q, map[string]interface{}{"first": 250}, "after", "products",
+//		func(node json.RawMessage) error {
+//			...
+//		})
+//
+// Every page is a Query, delayed until the shop has the points for it. See
+// QueryConnectionNodes to decode the nodes.
+func (s *GraphQLServiceOp) QueryConnection(ctx context.Context, q string, vars map[string]interface{}, cursorVar, path string, fn func(node json.RawMessage) error) error {
+	pageVars := make(map[string]interface{}, len(vars)+1)
+	for name, value := range vars {
+		pageVars[name] = value
+	}
+
+	for {
+		var data json.RawMessage
+		if err := s.Query(ctx, q, pageVars, &data); err != nil {
+			return err
+		}
+
+		connection, err := graphQLConnectionAt(data, path)
+		if err != nil || connection == nil {
+			return err
+		}
+
+		for _, node := range connection.Nodes {
+			if err := fn(node); err != nil {
+				return err
+			}
+		}
+		for _, edge := range connection.Edges {
+			if err := fn(edge.Node); err != nil {
+				return err
+			}
+		}
+
+		if !connection.PageInfo.HasNextPage {
+			return nil
+		}
+
+		cursor := connection.PageInfo.EndCursor
+		if cursor == "" || cursor == pageVars[cursorVar] {
+			return fmt.Errorf("graphql connection %s has a next page without a new endCursor", path)
+		}
+		pageVars[cursorVar] = cursor
+	}
+}
+
+// QueryConnectionNodes runs QueryConnection and calls fn with every node
+// decoded into a T.
+func QueryConnectionNodes[T any](ctx context.Context, service GraphQLService, q string, vars map[string]interface{}, cursorVar, path string, fn func(T) error) error {
+	return service.QueryConnection(ctx, q, vars, cursorVar, path, func(node json.RawMessage) error {
+		var v T
+		if err := json.Unmarshal(node, &v); err != nil {
+			return err
+		}
+		return fn(v)
+	})
+}
+
+// graphQLConnectionAt returns the connection at the dot separated path of the
+// data of a response, or nil when it is null
+func graphQLConnectionAt(data json.RawMessage, path string) (*graphQLConnection, error) {
+	value := data
+	for _, key := range strings.Split(path, ".") {
+		if len(value) == 0 || string(value) == "null" {
+			return nil, nil
+		}
+
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(value, &object); err != nil {
+			return nil, fmt.Errorf("graphql connection %s: %w", path, err)
+		}
+		var ok bool
+		if value, ok = object[key]; !ok {
+			return nil, fmt.Errorf("graphql connection %s: %s is not selected", path, key)
+		}
+	}
+	if len(value) == 0 || string(value) == "null" {
+		return nil, nil
+	}
+
+	connection := &graphQLConnection{}
+	if err := json.Unmarshal(value, connection); err != nil {
+		return nil, fmt.Errorf("graphql connection %s: %w", path, err)
+	}
+	if connection.PageInfo == nil {
+		return nil, fmt.Errorf("graphql connection %s doesn't select pageInfo { hasNextPage endCursor }", path)
+	}
+	return connection, nil
+}
@@ -0,0 +1,160 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestGraphQLQueryConnection(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var cursors []interface{}
+	registerGraphQL(func(operation string, vars map[string]interface{}) string {
+		cursors = append(cursors, vars["after"])
+		if vars["first"] != float64(2) {
+			t.Errorf("page requested with first = %v", vars["first"])
+		}
+		if vars["after"] == nil {
+			return `{"products":{"nodes":[{"id":1},{"id":2}],"pageInfo":{"hasNextPage":true,"endCursor":"c2"}}}`
+		}
+		return `{"products":{"nodes":[{"id":3}],"pageInfo":{"hasNextPage":false,"endCursor":"c3"}}}`
+	})
+
+	vars := map[string]interface{}{"first": 2}
+	var nodes []string
+	err := client.GraphQL.QueryConnection(context.Background(), "query products($first: Int!, $after: String) { ... }", vars, "after", "products",
+		func(node json.RawMessage) error {
+			nodes = append(nodes, string(node))
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("GraphQL.QueryConnection returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(nodes, []string{`{"id":1}`, `{"id":2}`, `{"id":3}`}) {
+		t.Errorf("GraphQL.QueryConnection emitted %v", nodes)
+	}
+	if !reflect.DeepEqual(cursors, []interface{}{nil, "c2"}) {
+		t.Errorf("GraphQL.QueryConnection requested cursors %v", cursors)
+	}
+	if _, ok := vars["after"]; ok {
+		t.Error("GraphQL.QueryConnection modified the variables")
+	}
+}
+
+func TestQueryConnectionNodesEdges(t *testing.T) {
+	setup()
+	defer teardown()
+
+	registerGraphQL(func(operation string, vars map[string]interface{}) string {
+		if vars["cursor"] == nil {
+			return `{"product":{"variants":{"edges":[{"node":{"sku":"S"}}],"pageInfo":{"hasNextPage":true,"endCursor":"v1"}}}}`
+		}
+		return `{"product":{"variants":{"edges":[{"node":{"sku":"M"}}],"pageInfo":{"hasNextPage":false,"endCursor":null}}}}`
+	})
+
+	type variant struct {
+		Sku string `json:"sku"`
+	}
+
+	var skus []string
+	err := QueryConnectionNodes(context.Background(), client.GraphQL, "query { ... }", nil, "cursor", "product.variants",
+		func(v variant) error {
+			skus = append(skus, v.Sku)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("QueryConnectionNodes returned error: %v", err)
+	}
+	if !reflect.DeepEqual(skus, []string{"S", "M"}) {
+		t.Errorf("QueryConnectionNodes emitted %v", skus)
+	}
+}
+
+func TestGraphQLQueryConnectionThrottled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// every page uses up the points, the next one waits for them to be restored
+	pages := 0
+	httpmock.RegisterResponder(
+		"POST",
+		fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			pages++
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{
+				"data":{"orders":{"nodes":[],"pageInfo":{"hasNextPage":%t,"endCursor":"o%d"}}},
+				"extensions":{"cost":{"requestedQueryCost":1,"throttleStatus":{"maximumAvailable":1000,"currentlyAvailable":0,"restoreRate":20}}}
+			}`, pages == 1, pages)), nil
+		},
+	)
+
+	start := time.Now()
+	err := client.GraphQL.QueryConnection(context.Background(), "query orders { ... }", nil, "after", "orders",
+		func(json.RawMessage) error { return nil })
+	if err != nil {
+		t.Fatalf("GraphQL.QueryConnection returned error: %v", err)
+	}
+	if pages != 2 {
+		t.Errorf("GraphQL.QueryConnection requested %d pages, expected 2", pages)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("GraphQL.QueryConnection took %v, expected to wait for the throttle", elapsed)
+	}
+}
+
+func TestGraphQLQueryConnectionErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	cases := []struct {
+		data     string
+		path     string
+		expected string
+	}{
+		{`{"products":{"nodes":[]}}`, "products", "graphql connection products doesn't select pageInfo { hasNextPage endCursor }"},
+		{`{"shop":{}}`, "shop.products", "graphql connection shop.products: products is not selected"},
+		{`{"products":{"nodes":[],"pageInfo":{"hasNextPage":true,"endCursor":""}}}`, "products", "graphql connection products has a next page without a new endCursor"},
+	}
+
+	for _, c := range cases {
+		registerGraphQL(func(string, map[string]interface{}) string { return c.data })
+
+		err := client.GraphQL.QueryConnection(context.Background(), "query { ... }", nil, "after", c.path,
+			func(json.RawMessage) error { return nil })
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("GraphQL.QueryConnection returned %v, expected %s", err, c.expected)
+		}
+	}
+
+	// a null connection has no nodes
+	registerGraphQL(func(string, map[string]interface{}) string { return `{"product":null}` })
+	err := client.GraphQL.QueryConnection(context.Background(), "query { ... }", nil, "after", "product.variants",
+		func(json.RawMessage) error {
+			t.Error("a null connection emitted a node")
+			return nil
+		})
+	if err != nil {
+		t.Errorf("GraphQL.QueryConnection returned error: %v", err)
+	}
+
+	// errors of fn stop the pagination
+	registerGraphQL(func(string, map[string]interface{}) string {
+		return `{"products":{"nodes":[{"id":1}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}`
+	})
+	failure := errors.New("disk full")
+	err = client.GraphQL.QueryConnection(context.Background(), "query { ... }", nil, "after", "products",
+		func(json.RawMessage) error { return failure })
+	if err != failure {
+		t.Errorf("GraphQL.QueryConnection returned %v, expected %v", err, failure)
+	}
+}
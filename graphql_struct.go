@@ -0,0 +1,394 @@
+package goshopify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// GraphQLVariable is a variable of a struct query with an explicit GraphQL
+// type, for the types that can't be told from the Go value:
+//
+//	vars := map[string]interface{}{
+//		"id": goshopify.GraphQLVariable{Type: "ID!", Value: "gid://shopify/Product/1"},
+//	}
+type GraphQLVariable struct {
+	Type  string
+	Value interface{}
+}
+
+// MarshalJSON sends the value of the variable.
+func (v GraphQLVariable) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Value)
+}
+
+// GraphQLTyper is implemented by variable values of custom types to declare
+// their GraphQL type, e.g. "ProductInput!".
+type GraphQLTyper interface {
+	GraphQLType() string
+}
+
+var (
+	graphQLTyperType      = reflect.TypeOf((*GraphQLTyper)(nil)).Elem()
+	jsonUnmarshalerType   = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	timeType              = reflect.TypeOf(time.Time{})
+	graphQLVariableGoType = reflect.TypeOf(GraphQLVariable{})
+)
+
+const graphQLFragmentStart = "..."
+
+// QueryStruct builds the query of q, a pointer to a struct, see
+// BuildGraphQLQuery, runs it like Query and decodes the response into q.
+func (s *GraphQLServiceOp) QueryStruct(ctx context.Context, q interface{}, vars map[string]interface{}) error {
+	query, err := BuildGraphQLQuery(q, vars)
+	if err != nil {
+		return err
+	}
+	_, err = s.runStruct(ctx, query, q, vars)
+	return err
+}
+
+// MutateStruct builds the mutation of m, a pointer to a struct, see
+// BuildGraphQLMutation, runs it like Mutate and decodes the response into m.
+func (s *GraphQLServiceOp) MutateStruct(ctx context.Context, m interface{}, vars map[string]interface{}) error {
+	mutation, err := BuildGraphQLMutation(m, vars)
+	if err != nil {
+		return err
+	}
+	data, err := s.runStruct(ctx, mutation, m, vars)
+	if err != nil {
+		return err
+	}
+	return mutationUserErrors(data)
+}
+
+func (s *GraphQLServiceOp) runStruct(ctx context.Context, q string, v interface{}, vars map[string]interface{}) (json.RawMessage, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, fmt.Errorf("graphql response must be decoded into a non-nil pointer, got %T", v)
+	}
+
+	var data json.RawMessage
+	if err := s.Query(ctx, q, vars, &data); err != nil {
+		return nil, err
+	}
+	return data, decodeGraphQL(data, rv.Elem())
+}
+
+// BuildGraphQLQuery returns the query selecting the fields of q, a struct or
+// a pointer to one, with a declaration of every variable of vars.
+//
+// The selection of a field is its graphql tag, or its name in lower camel
+// case, followed by the selection set of its fields when it is a struct, a
+// slice of structs or a pointer to one. A tag may have arguments, an alias or
+// directives, a tag starting with "..." is an inline fragment whose fields are
+// decoded from the same object, and "-" skips the field. Embedded structs
+// without a tag are flattened.
+//
+//	var q struct {
+//		Products struct {
+//			Edges []struct {
+//				Node struct {
+//					Id    string
+//					Title string
+//					Tags  []string
+//				}
+//			}
+//		} `graphql:"products(first: $first, query: $query)"`
+//		Node struct {
+//			Product struct {
+//				Handle string
+//			} `graphql:"... on Product"`
+//		} `graphql:"product: node(id: $id)"`
+//	}
+//
+// Structs implementing json.Unmarshaler, and time.Time, are scalars.
+//
+// The type of a variable is the GraphQLType of its value, the Type of a
+// GraphQLVariable or is told from its Go type: String!, Int!, Float!,
+// Boolean! and DateTime! for the basic types and time.Time, the name of the
+// Go type of structs, [T]! for slices and without the ! for pointers.
+func BuildGraphQLQuery(q interface{}, vars map[string]interface{}) (string, error) {
+	return buildGraphQLOperation("query", q, vars)
+}
+
+// BuildGraphQLMutation returns the mutation selecting the fields of m, see
+// BuildGraphQLQuery.
+func BuildGraphQLMutation(m interface{}, vars map[string]interface{}) (string, error) {
+	return buildGraphQLOperation("mutation", m, vars)
+}
+
+func buildGraphQLOperation(operation string, v interface{}, vars map[string]interface{}) (string, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return "", fmt.Errorf("graphql %s must be a struct, got %T", operation, v)
+	}
+
+	b := &bytes.Buffer{}
+	b.WriteString(operation)
+
+	// a named struct names the operation, e.g. for instrumentation
+	if t.Name() != "" {
+		b.WriteString(" " + t.Name())
+	}
+
+	if len(vars) > 0 {
+		names := make([]string, 0, len(vars))
+		for name := range vars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		b.WriteString("(")
+		for i, name := range names {
+			typ, err := graphQLVariableType(vars[name])
+			if err != nil {
+				return "", fmt.Errorf("graphql variable %s: %w", name, err)
+			}
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(b, "$%s: %s", name, typ)
+		}
+		b.WriteString(")")
+	}
+
+	if err := writeGraphQLSelection(b, t, map[reflect.Type]bool{}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// writeGraphQLSelection writes the selection set of the fields of a struct
+func writeGraphQLSelection(b *bytes.Buffer, t reflect.Type, visiting map[reflect.Type]bool) error {
+	if visiting[t] {
+		return fmt.Errorf("graphql selection of %s is recursive", t)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	b.WriteString(" {")
+	if err := writeGraphQLFields(b, t, visiting); err != nil {
+		return err
+	}
+	b.WriteString(" }")
+	return nil
+}
+
+func writeGraphQLFields(b *bytes.Buffer, t reflect.Type, visiting map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		selection, ok := graphQLFieldSelection(field)
+		if !ok {
+			continue
+		}
+
+		if selection == "" {
+			// embedded struct without a tag
+			if err := writeGraphQLFields(b, graphQLObjectType(field.Type), visiting); err != nil {
+				return err
+			}
+			continue
+		}
+
+		b.WriteString(" " + selection)
+		if object := graphQLObjectType(field.Type); object != nil {
+			if err := writeGraphQLSelection(b, object, visiting); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// graphQLFieldSelection returns the selection of a struct field, empty for
+// embedded structs to flatten, and whether the field is selected at all
+func graphQLFieldSelection(field reflect.StructField) (string, bool) {
+	tag, tagged := field.Tag.Lookup("graphql")
+	if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+		// skipped or unexported
+		return "", false
+	}
+	if tagged && tag != "" {
+		return tag, true
+	}
+	if field.Anonymous {
+		return "", graphQLObjectType(field.Type) != nil
+	}
+	return lowerCamelCase(field.Name), true
+}
+
+// graphQLResponseKey returns the key of a selection in the response, e.g.
+// "shirts" for `shirts: products(query: "shirt")`, or "" for fragments
+func graphQLResponseKey(selection string) string {
+	if strings.HasPrefix(selection, graphQLFragmentStart) {
+		return ""
+	}
+
+	name := selection
+	if i := strings.IndexAny(name, "(@"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		// alias
+		name = name[:i]
+	}
+	return strings.TrimSpace(name)
+}
+
+// graphQLObjectType returns the struct whose fields are selected for a field
+// of type t, or nil when t is a scalar
+func graphQLObjectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType || reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+	return t
+}
+
+// graphQLVariableType returns the GraphQL type of a variable's value
+func graphQLVariableType(v interface{}) (string, error) {
+	if v == nil {
+		return "", fmt.Errorf("cannot tell the type of nil, use a GraphQLVariable")
+	}
+	switch typed := v.(type) {
+	case GraphQLVariable:
+		return typed.Type, nil
+	case GraphQLTyper:
+		return typed.GraphQLType(), nil
+	}
+	return graphQLGoType(reflect.TypeOf(v))
+}
+
+func graphQLGoType(t reflect.Type) (string, error) {
+	// a nil pointer can't tell its type, the type of its element is used
+	if t.Kind() != reflect.Ptr && t.Implements(graphQLTyperType) {
+		return reflect.Zero(t).Interface().(GraphQLTyper).GraphQLType(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem, err := graphQLGoType(t.Elem())
+		return strings.TrimSuffix(elem, "!"), err
+	case reflect.Slice, reflect.Array:
+		elem, err := graphQLGoType(t.Elem())
+		return "[" + elem + "]!", err
+	case reflect.String:
+		return "String!", nil
+	case reflect.Bool:
+		return "Boolean!", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "Int!", nil
+	case reflect.Float32, reflect.Float64:
+		return "Float!", nil
+	case reflect.Struct:
+		if t == timeType {
+			return "DateTime!", nil
+		}
+		if t.Name() != "" && t != graphQLVariableGoType {
+			return t.Name() + "!", nil
+		}
+	}
+	return "", fmt.Errorf("cannot tell the graphql type of %s, use a GraphQLVariable", t)
+}
+
+// lowerCamelCase converts a Go field name, e.g. "ProductType" to
+// "productType", "Id" to "id" and "HTMLBody" to "htmlBody"
+func lowerCamelCase(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			// first letter of the next word
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// decodeGraphQL decodes the data of a struct query into v, matching the fields
+// by their selection rather than their json tag
+func decodeGraphQL(data json.RawMessage, v reflect.Value) error {
+	if len(data) == 0 || string(data) == "null" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeGraphQL(data, v.Elem())
+
+	case reflect.Slice:
+		if graphQLObjectType(v.Type()) == nil {
+			break
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeGraphQL(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+
+	case reflect.Struct:
+		if graphQLObjectType(v.Type()) == nil {
+			break
+		}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		return decodeGraphQLFields(data, object, v)
+	}
+
+	return json.Unmarshal(data, v.Addr().Interface())
+}
+
+func decodeGraphQLFields(data json.RawMessage, object map[string]json.RawMessage, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		selection, ok := graphQLFieldSelection(t.Field(i))
+		if !ok {
+			continue
+		}
+
+		field := v.Field(i)
+		key := graphQLResponseKey(selection)
+		if key == "" {
+			// embedded structs and fragments are decoded from the same object
+			if err := decodeGraphQL(data, field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := object[key]
+		if !ok {
+			continue
+		}
+		if err := decodeGraphQL(value, field); err != nil {
+			return fmt.Errorf("graphql field %s: %w", key, err)
+		}
+	}
+	return nil
+}
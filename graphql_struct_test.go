@@ -0,0 +1,252 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+type productInput struct {
+	Id    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type typedQuantity int
+
+func (typedQuantity) GraphQLType() string { return "Int" }
+
+type structQueryNode struct {
+	Typename string `graphql:"__typename"`
+	Product  struct {
+		Handle string
+	} `graphql:"... on Product"`
+	Collection *struct {
+		SortOrder string
+	} `graphql:"... on Collection"`
+}
+
+type structQueryShop struct {
+	Name string
+}
+
+type productsQuery struct {
+	Products struct {
+		Edges []struct {
+			Node struct {
+				Id        string
+				Title     string
+				CreatedAt time.Time
+				Tags      []string
+			}
+		}
+	} `graphql:"products(first: $first, query: $query)"`
+	Shirts struct {
+		Nodes []struct {
+			Id string
+		}
+	} `graphql:"shirts: products(first: 1, query: \"shirt\")"`
+	Node *structQueryNode `graphql:"node(id: $id)"`
+	structQueryShop
+	Ignored string `graphql:"-"`
+	ignored string
+}
+
+func TestBuildGraphQLQuery(t *testing.T) {
+	vars := map[string]interface{}{
+		"first": 10,
+		"query": (*string)(nil),
+		"id":    GraphQLVariable{Type: "ID!", Value: "gid://shopify/Product/1"},
+	}
+
+	query, err := BuildGraphQLQuery(&productsQuery{}, vars)
+	if err != nil {
+		t.Fatalf("BuildGraphQLQuery returned error: %v", err)
+	}
+
+	expected := `query productsQuery($first: Int!, $id: ID!, $query: String) {` +
+		` products(first: $first, query: $query) { edges { node { id title createdAt tags } } }` +
+		` shirts: products(first: 1, query: "shirt") { nodes { id } }` +
+		` node(id: $id) { __typename ... on Product { handle } ... on Collection { sortOrder } }` +
+		` name }`
+	if query != expected {
+		t.Errorf("BuildGraphQLQuery returned\n%s\nexpected\n%s", query, expected)
+	}
+}
+
+func TestBuildGraphQLMutation(t *testing.T) {
+	var m struct {
+		ProductUpdate struct {
+			Product struct {
+				Id string
+			}
+			UserErrors []GraphQLUserError
+		} `graphql:"productUpdate(input: $input)"`
+	}
+	vars := map[string]interface{}{
+		"input":      productInput{Id: "gid://shopify/Product/1"},
+		"inputs":     []productInput{},
+		"quantity":   typedQuantity(1),
+		"quantities": []*int{},
+		"at":         time.Now(),
+		"published":  true,
+		"price":      1.5,
+	}
+
+	mutation, err := BuildGraphQLMutation(&m, vars)
+	if err != nil {
+		t.Fatalf("BuildGraphQLMutation returned error: %v", err)
+	}
+
+	expected := `mutation($at: DateTime!, $input: productInput!, $inputs: [productInput!]!, $price: Float!, $published: Boolean!, $quantities: [Int]!, $quantity: Int) {` +
+		` productUpdate(input: $input) { product { id } userErrors { field message } } }`
+	if mutation != expected {
+		t.Errorf("BuildGraphQLMutation returned\n%s\nexpected\n%s", mutation, expected)
+	}
+}
+
+type recursiveQuery struct {
+	Parent *recursiveQuery
+}
+
+func TestBuildGraphQLQueryErrors(t *testing.T) {
+	cases := []struct {
+		q        interface{}
+		vars     map[string]interface{}
+		expected string
+	}{
+		{"{ shop { name } }", nil, "graphql query must be a struct, got string"},
+		{nil, nil, "graphql query must be a struct, got <nil>"},
+		{struct{}{}, map[string]interface{}{"id": nil}, "graphql variable id: cannot tell the type of nil, use a GraphQLVariable"},
+		{struct{}{}, map[string]interface{}{"input": map[string]string{}}, "graphql variable input: cannot tell the graphql type of map[string]string, use a GraphQLVariable"},
+		{recursiveQuery{}, nil, "graphql selection of goshopify.recursiveQuery is recursive"},
+	}
+
+	for _, c := range cases {
+		_, err := BuildGraphQLQuery(c.q, c.vars)
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("BuildGraphQLQuery(%T) returned %v, expected %s", c.q, err, c.expected)
+		}
+	}
+}
+
+func TestGraphQLQueryStruct(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var body struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	httpmock.RegisterResponder(
+		"POST",
+		fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			return httpmock.NewStringResponse(200, `{"data":{
+				"products":{"edges":[{"node":{"id":"gid://shopify/Product/1","title":"Shirt","createdAt":"2024-01-02T03:04:05Z","tags":["a","b"]}}]},
+				"shirts":{"nodes":[{"id":"gid://shopify/Product/2"}]},
+				"node":{"__typename":"Product","handle":"shirt"},
+				"name":"Foo shop"
+			}}`), nil
+		},
+	)
+
+	q := productsQuery{Ignored: "kept"}
+	vars := map[string]interface{}{
+		"first": 10,
+		"query": (*string)(nil),
+		"id":    GraphQLVariable{Type: "ID!", Value: "gid://shopify/Product/1"},
+	}
+	if err := client.GraphQL.QueryStruct(context.Background(), &q, vars); err != nil {
+		t.Fatalf("GraphQL.QueryStruct returned error: %v", err)
+	}
+
+	expectedVars := map[string]interface{}{"first": float64(10), "query": nil, "id": "gid://shopify/Product/1"}
+	if !reflect.DeepEqual(body.Variables, expectedVars) {
+		t.Errorf("GraphQL.QueryStruct sent variables %v, expected %v", body.Variables, expectedVars)
+	}
+	if expected, _ := BuildGraphQLQuery(&q, vars); body.Query != expected {
+		t.Errorf("GraphQL.QueryStruct sent query %s", body.Query)
+	}
+
+	edges := q.Products.Edges
+	if len(edges) != 1 || edges[0].Node.Title != "Shirt" || !reflect.DeepEqual(edges[0].Node.Tags, []string{"a", "b"}) ||
+		!edges[0].Node.CreatedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("products = %+v", q.Products)
+	}
+	if len(q.Shirts.Nodes) != 1 || q.Shirts.Nodes[0].Id != "gid://shopify/Product/2" {
+		t.Errorf("shirts = %+v", q.Shirts)
+	}
+	if q.Node == nil || q.Node.Typename != "Product" || q.Node.Product.Handle != "shirt" {
+		t.Errorf("node = %+v", q.Node)
+	}
+	if q.Name != "Foo shop" || q.Ignored != "kept" {
+		t.Errorf("name = %q, ignored = %q", q.Name, q.Ignored)
+	}
+}
+
+func TestGraphQLMutateStruct(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder(
+		"POST",
+		fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{"productUpdate":{"product":null,"userErrors":[{"field":["input","title"],"message":"Title can't be blank"}]}}}`),
+	)
+
+	var m struct {
+		ProductUpdate struct {
+			Product *struct {
+				Id string
+			}
+			UserErrors []GraphQLUserError
+		} `graphql:"productUpdate(input: $input)"`
+	}
+	m.ProductUpdate.Product = &struct{ Id string }{Id: "stale"}
+
+	err := client.GraphQL.MutateStruct(context.Background(), &m, map[string]interface{}{"input": productInput{}})
+	var userErrors GraphQLUserErrors
+	if !errors.As(err, &userErrors) {
+		t.Fatalf("GraphQL.MutateStruct returned %v, expected GraphQLUserErrors", err)
+	}
+
+	if m.ProductUpdate.Product != nil {
+		t.Errorf("product = %+v, expected nil", m.ProductUpdate.Product)
+	}
+	expected := []GraphQLUserError{{Field: []string{"input", "title"}, Message: "Title can't be blank"}}
+	if !reflect.DeepEqual(m.ProductUpdate.UserErrors, expected) {
+		t.Errorf("userErrors = %+v, expected %+v", m.ProductUpdate.UserErrors, expected)
+	}
+
+	if err := client.GraphQL.MutateStruct(context.Background(), m, nil); err == nil {
+		t.Error("GraphQL.MutateStruct into a struct should return an error")
+	}
+}
+
+func TestLowerCamelCase(t *testing.T) {
+	cases := map[string]string{
+		"Title":       "title",
+		"Id":          "id",
+		"ID":          "id",
+		"ProductType": "productType",
+		"HTMLBody":    "htmlBody",
+		"SKU":         "sku",
+		"already":     "already",
+	}
+
+	for name, expected := range cases {
+		if actual := lowerCamelCase(name); actual != expected {
+			t.Errorf("lowerCamelCase(%q) = %q, expected %q", name, actual, expected)
+		}
+	}
+}
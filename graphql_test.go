@@ -2,6 +2,7 @@ package goshopify
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -105,6 +106,10 @@ func TestGraphQLQueryWithRetries(t *testing.T) {
 				ResponseError: ResponseError{
 					Status:  200,
 					Message: "Throttled",
+					GraphQLErrors: []GraphQLError{{
+						Message:    "Throttled",
+						Extensions: &GraphQLErrorExtensions{Code: "THROTTLED"},
+					}},
 				},
 				RetryAfter: 2,
 			},
@@ -184,6 +189,8 @@ func TestGraphQLQueryWithRetries(t *testing.T) {
 		t.Run(c.description, func(t *testing.T) {
 			// used to track retries in case clojure
 			retries = c.retries
+			// don't carry the throttle status over from the previous case
+			client.graphQLThrottle = newGraphQLThrottle()
 
 			requestURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix)
 
@@ -371,3 +378,112 @@ func TestGraphQLCostRetryAfterSeconds(t *testing.T) {
 func makeIntPointer(v int) *int {
 	return &v
 }
+
+func TestGraphQLQueryErrorDetails(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder(
+		"POST",
+		fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"errors":[{
+			"message":"Access denied for metafield field.",
+			"locations":[{"line":1,"column":32}],
+			"path":["products","edges",0,"node","metafield"],
+			"extensions":{"code":"ACCESS_DENIED","documentation":"https://shopify.dev/api/usage/access-scopes"}
+		}]}`),
+	)
+
+	err := client.GraphQL.Query(context.Background(), "query {}", nil, nil)
+
+	var responseError ResponseError
+	if !errors.As(err, &responseError) {
+		t.Fatalf("GraphQL.Query returned %v, expected a ResponseError", err)
+	}
+
+	expected := []GraphQLError{{
+		Message:   "Access denied for metafield field.",
+		Path:      []interface{}{"products", "edges", float64(0), "node", "metafield"},
+		Locations: []GraphQLErrorLocation{{Line: 1, Column: 32}},
+		Extensions: &GraphQLErrorExtensions{
+			Code:          "ACCESS_DENIED",
+			Documentation: "https://shopify.dev/api/usage/access-scopes",
+		},
+	}}
+	if !reflect.DeepEqual(responseError.GetGraphQLErrors(), expected) {
+		t.Errorf("GraphQLErrors = %#v, expected %#v", responseError.GraphQLErrors, expected)
+	}
+	if code := responseError.GraphQLErrors[0].Code(); code != "ACCESS_DENIED" {
+		t.Errorf("Code() = %q, expected ACCESS_DENIED", code)
+	}
+	if err.Error() != "Access denied for metafield field." {
+		t.Errorf("GraphQL.Query returned error message %q", err.Error())
+	}
+}
+
+func TestGraphQLMutate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder(
+		"POST",
+		fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{"productCreate":{
+			"product":{"id":"gid://shopify/Product/1"},
+			"userErrors":[{"field":["input","handle"],"message":"Handle has already been taken","code":"TAKEN"}]
+		}}}`),
+	)
+
+	resp := struct {
+		ProductCreate struct {
+			Product struct {
+				Id string `json:"id"`
+			} `json:"product"`
+		} `json:"productCreate"`
+	}{}
+	err := client.GraphQL.Mutate(context.Background(), "mutation productCreate { ... }", nil, &resp)
+
+	var userErrors GraphQLUserErrors
+	if !errors.As(err, &userErrors) {
+		t.Fatalf("GraphQL.Mutate returned %v, expected GraphQLUserErrors", err)
+	}
+	if !userErrors.HasCode("TAKEN") || userErrors.HasCode("INVALID") {
+		t.Errorf("GraphQL.Mutate returned user errors %+v", userErrors)
+	}
+	if err.Error() != "input.handle: Handle has already been taken" {
+		t.Errorf("GraphQL.Mutate returned error message %q", err.Error())
+	}
+	if resp.ProductCreate.Product.Id != "gid://shopify/Product/1" {
+		t.Errorf("GraphQL.Mutate decoded %+v", resp)
+	}
+}
+
+func TestGraphQLMutateWithoutUserErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	cases := []struct {
+		data     string
+		expected error
+	}{
+		{`{"productDelete":{"deletedProductId":"gid://shopify/Product/1","userErrors":[]}}`, nil},
+		{`{"productDelete":null}`, nil},
+		{
+			`{"customerCreate":{"customer":null,"customerUserErrors":[{"field":["email"],"message":"Email is invalid","code":"INVALID"}]}}`,
+			GraphQLUserErrors{{Field: []string{"email"}, Message: "Email is invalid", Code: "INVALID"}},
+		},
+	}
+
+	for _, c := range cases {
+		httpmock.RegisterResponder(
+			"POST",
+			fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+			httpmock.NewStringResponder(200, `{"data":`+c.data+`}`),
+		)
+
+		err := client.GraphQL.Mutate(context.Background(), "mutation { ... }", nil, nil)
+		if !reflect.DeepEqual(err, c.expected) {
+			t.Errorf("GraphQL.Mutate returned %#v, expected %#v", err, c.expected)
+		}
+	}
+}
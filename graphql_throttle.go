@@ -0,0 +1,118 @@
+package goshopify
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// upper bound of distinct queries whose cost is remembered
+const maxGraphQLQueryCosts = 1000
+
+// graphQLThrottle tracks the shop's GraphQL cost bucket across queries so that
+// a query is only sent once enough points are estimated to be available.
+type graphQLThrottle struct {
+	mu sync.Mutex
+
+	// last throttle status reported by Shopify, less the points reserved by
+	// queries sent since then
+	status    GraphQLThrottleStatus
+	updatedAt time.Time
+
+	// last requested cost of each query and of any query
+	costs    map[string]int
+	lastCost int
+
+	// Internal testing use only.
+	now func() time.Time
+}
+
+func newGraphQLThrottle() *graphQLThrottle {
+	return &graphQLThrottle{
+		costs: make(map[string]int),
+		now:   time.Now,
+	}
+}
+
+// Wait blocks until the estimated cost of the query is available, or until
+// ctx is done in which case the context's error is returned. The points
+// reserved for the query are returned so they can be refunded if the query
+// never reaches Shopify.
+func (t *graphQLThrottle) Wait(ctx context.Context, query string) (float64, error) {
+	for {
+		reserved, wait := t.reserve(query)
+		if wait <= 0 {
+			return reserved, nil
+		}
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// reserve deducts the estimated cost of the query from the available points,
+// or returns how long to wait until enough points are restored.
+func (t *graphQLThrottle) reserve(query string) (float64, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// nothing is known until the first response is observed
+	if t.updatedAt.IsZero() || t.status.RestoreRate <= 0 {
+		return 0, 0
+	}
+
+	cost, ok := t.costs[query]
+	if !ok {
+		cost = t.lastCost
+	}
+	estimate := math.Min(float64(cost), t.status.MaximumAvailable)
+
+	now := t.now()
+	available := t.availableAt(now)
+	if estimate > available {
+		wait := (estimate - available) / t.status.RestoreRate
+		return 0, time.Duration(wait * float64(time.Second))
+	}
+
+	t.status.CurrentlyAvailable = available - estimate
+	t.updatedAt = now
+	return estimate, 0
+}
+
+// refund returns points reserved for a query which did not report its cost,
+// e.g. because the request failed before reaching the GraphQL endpoint.
+func (t *graphQLThrottle) refund(points float64) {
+	if points <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.status.CurrentlyAvailable = math.Min(t.availableAt(now)+points, t.status.MaximumAvailable)
+	t.updatedAt = now
+}
+
+// availableAt returns the points restored by the given time. Callers must hold
+// t.mu.
+func (t *graphQLThrottle) availableAt(now time.Time) float64 {
+	restored := now.Sub(t.updatedAt).Seconds() * t.status.RestoreRate
+	return math.Min(t.status.CurrentlyAvailable+restored, t.status.MaximumAvailable)
+}
+
+// observe records the cost extension returned for the query.
+func (t *graphQLThrottle) observe(query string, cost GraphQLCost) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.costs[query]; !ok && len(t.costs) >= maxGraphQLQueryCosts {
+		t.costs = make(map[string]int)
+	}
+	t.costs[query] = cost.RequestedQueryCost
+	t.lastCost = cost.RequestedQueryCost
+	t.status = cost.ThrottleStatus
+	t.updatedAt = t.now()
+}
@@ -0,0 +1,104 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestGraphQLThrottleReserve(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	throttle := newGraphQLThrottle()
+	throttle.now = clock.Now
+
+	// nothing to go on before the first response
+	if reserved, wait := throttle.reserve("query {}"); reserved != 0 || wait != 0 {
+		t.Errorf("reserve() before observe = %v, %s expected 0, 0s", reserved, wait)
+	}
+
+	throttle.observe("query {}", GraphQLCost{
+		RequestedQueryCost: 100,
+		ThrottleStatus: GraphQLThrottleStatus{
+			MaximumAvailable:   1000,
+			CurrentlyAvailable: 150,
+			RestoreRate:        50,
+		},
+	})
+
+	if reserved, wait := throttle.reserve("query {}"); reserved != 100 || wait != 0 {
+		t.Errorf("reserve() = %v, %s expected 100, 0s", reserved, wait)
+	}
+
+	// 50 points left, the query needs 100 restored at 50 per second
+	if reserved, wait := throttle.reserve("query {}"); reserved != 0 || wait != time.Second {
+		t.Errorf("reserve() = %v, %s expected 0, 1s", reserved, wait)
+	}
+
+	// unknown queries are estimated from the last requested cost
+	clock.Advance(time.Second)
+	if reserved, wait := throttle.reserve("query { other }"); reserved != 100 || wait != 0 {
+		t.Errorf("reserve() of unknown query = %v, %s expected 100, 0s", reserved, wait)
+	}
+
+	throttle.refund(100)
+	if throttle.status.CurrentlyAvailable != 100 {
+		t.Errorf("refund() CurrentlyAvailable = %v, expected 100", throttle.status.CurrentlyAvailable)
+	}
+
+	// points are never restored past the maximum
+	clock.Advance(time.Minute)
+	if available := throttle.availableAt(clock.Now()); available != 1000 {
+		t.Errorf("availableAt() = %v, expected 1000", available)
+	}
+}
+
+func TestGraphQLThrottleWaitContextCancelled(t *testing.T) {
+	throttle := newGraphQLThrottle()
+	throttle.observe("query {}", GraphQLCost{
+		RequestedQueryCost: 1000,
+		ThrottleStatus: GraphQLThrottleStatus{
+			MaximumAvailable:   1000,
+			CurrentlyAvailable: 0,
+			RestoreRate:        1,
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := throttle.Wait(ctx, "query {}"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() returned %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestGraphQLQueryWaitsForThrottle(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix)
+	httpmock.RegisterResponder("POST", requestURL,
+		httpmock.NewStringResponder(200, `{"data":{"foo":"bar"},"extensions":{"cost":{"requestedQueryCost":50,"actualQueryCost":50,"throttleStatus":{"maximumAvailable":1000,"currentlyAvailable":0,"restoreRate":1000}}}}`))
+
+	resp := struct {
+		Foo string `json:"foo"`
+	}{}
+	if err := client.GraphQL.Query(context.Background(), "query {}", nil, &resp); err != nil {
+		t.Fatalf("GraphQL.Query returned error: %v", err)
+	}
+
+	// the bucket is empty and the query costs 50 points restored at 1000/s
+	start := time.Now()
+	if err := client.GraphQL.Query(context.Background(), "query {}", nil, &resp); err != nil {
+		t.Fatalf("GraphQL.Query returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("GraphQL.Query took %s, expected to wait at least 50ms", elapsed)
+	}
+
+	if calls := httpmock.GetCallCountInfo()["POST "+requestURL]; calls != 2 {
+		t.Errorf("GraphQL.Query made %d requests, expected 2", calls)
+	}
+}
@@ -0,0 +1,182 @@
+package goshopify
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Instrumenter receives structured events about the requests made by a
+// client, see WithInstrumenter. It is called concurrently when the client is
+// shared between goroutines.
+//
+// MetricsInstrumenter and TracingInstrumenter adapt these events to
+// Prometheus style metrics and OpenTelemetry style spans.
+type Instrumenter interface {
+	// RequestStart is called before every attempt of a REST or GraphQL
+	// request is sent. The returned context is used to send the attempt and is
+	// passed to RequestEnd, e.g. to carry a span.
+	RequestStart(ctx context.Context, event RequestEvent) context.Context
+
+	// RequestEnd is called once the response of an attempt has been received,
+	// or the attempt failed.
+	RequestEnd(ctx context.Context, event RequestEvent)
+
+	// GraphQLStart is called before every attempt of a GraphQL query. The
+	// returned context is used for the query and passed to GraphQLEnd.
+	GraphQLStart(ctx context.Context, event GraphQLEvent) context.Context
+
+	// GraphQLEnd is called once a GraphQL query attempt has completed.
+	GraphQLEnd(ctx context.Context, event GraphQLEvent)
+}
+
+// RequestEvent describes a single attempt of a request.
+type RequestEvent struct {
+	// Resource names the endpoint and action, e.g. "orders.list",
+	// "products.variants.update" or "graphql".
+	Resource string
+	Method   string
+	Path     string
+
+	// Attempt is 1 for the first attempt and increases with every retry.
+	Attempt int
+
+	// ThrottleWait is the time spent waiting on the retry backoff and the rate
+	// limiter before the attempt was sent, not counting earlier attempts.
+	ThrottleWait time.Duration
+
+	// The fields below are only set for RequestEnd. StatusCode is 0 when no
+	// response was received.
+	StatusCode int
+	Duration   time.Duration
+	RequestId  string
+	Err        error
+
+	// REST bucket usage from the X-Shopify-Shop-Api-Call-Limit header
+	RequestCount int
+	BucketSize   int
+}
+
+// GraphQLEvent describes a single attempt of a GraphQL query.
+type GraphQLEvent struct {
+	// Operation is the name of the query or mutation, "anonymous" if unnamed.
+	Operation string
+
+	// Attempt is 1 for the first attempt and increases with every retry.
+	Attempt int
+
+	// ThrottleWait is the time spent waiting for the estimated cost of the
+	// query to be available.
+	ThrottleWait time.Duration
+
+	// The fields below are only set for GraphQLEnd. Cost is nil when Shopify
+	// did not report it.
+	Duration time.Duration
+	Cost     *GraphQLCost
+	Err      error
+}
+
+// MultiInstrumenter reports events to every given instrumenter in order.
+func MultiInstrumenter(instrumenters ...Instrumenter) Instrumenter {
+	return multiInstrumenter(instrumenters)
+}
+
+type multiInstrumenter []Instrumenter
+
+func (m multiInstrumenter) RequestStart(ctx context.Context, event RequestEvent) context.Context {
+	for _, i := range m {
+		ctx = i.RequestStart(ctx, event)
+	}
+	return ctx
+}
+
+func (m multiInstrumenter) RequestEnd(ctx context.Context, event RequestEvent) {
+	for _, i := range m {
+		i.RequestEnd(ctx, event)
+	}
+}
+
+func (m multiInstrumenter) GraphQLStart(ctx context.Context, event GraphQLEvent) context.Context {
+	for _, i := range m {
+		ctx = i.GraphQLStart(ctx, event)
+	}
+	return ctx
+}
+
+func (m multiInstrumenter) GraphQLEnd(ctx context.Context, event GraphQLEvent) {
+	for _, i := range m {
+		i.GraphQLEnd(ctx, event)
+	}
+}
+
+// noopInstrumenter is used when no instrumenter is configured
+type noopInstrumenter struct{}
+
+func (noopInstrumenter) RequestStart(ctx context.Context, _ RequestEvent) context.Context { return ctx }
+func (noopInstrumenter) RequestEnd(context.Context, RequestEvent)                         {}
+func (noopInstrumenter) GraphQLStart(ctx context.Context, _ GraphQLEvent) context.Context { return ctx }
+func (noopInstrumenter) GraphQLEnd(context.Context, GraphQLEvent)                         {}
+
+// matches the api prefix of a resource path, e.g. "/admin/api/2024-01/"
+var resourcePathPrefixRegex = regexp.MustCompile(`^/?admin/(api/[^/]+/)?`)
+
+// resourceName derives a low cardinality name for the endpoint of a request,
+// dropping ids from the path and naming the action by the method, e.g.
+// "GET /admin/api/2024-01/orders/1/risks.json" becomes "orders.risks.list".
+func resourceName(method, path string) string {
+	path = resourcePathPrefixRegex.ReplaceAllString(path, "")
+	path = strings.TrimSuffix(path, ".json")
+
+	var parts []string
+	endsWithId := false
+	for _, part := range strings.Split(path, "/") {
+		if part == "" {
+			continue
+		}
+		if isResourceId(part) {
+			endsWithId = true
+			continue
+		}
+		endsWithId = false
+		parts = append(parts, part)
+	}
+
+	if len(parts) == 0 {
+		return strings.ToLower(method)
+	}
+
+	last := parts[len(parts)-1]
+	if last == "graphql" || last == "count" {
+		return strings.Join(parts, ".")
+	}
+
+	var action string
+	switch method {
+	case "GET":
+		action = "list"
+		if endsWithId {
+			action = "get"
+		}
+	case "POST":
+		action = "create"
+	case "PUT":
+		action = "update"
+	case "DELETE":
+		action = "delete"
+	default:
+		action = strings.ToLower(method)
+	}
+
+	return strings.Join(parts, ".") + "." + action
+}
+
+// isResourceId reports whether a path segment is a numeric id.
+func isResourceId(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,185 @@
+package goshopify
+
+import (
+	"context"
+	"strconv"
+)
+
+// MetricsInstrumenter is an Instrumenter feeding Prometheus style counters,
+// histograms and gauges. Every field is optional, e.g. with Prometheus:
+//
+//	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "shopify_requests_total"},
+//		[]string{"resource", "method", "status"})
+//	instrumenter := &goshopify.MetricsInstrumenter{
+//		CountRequest: func(resource, method, status string) {
+//			requests.WithLabelValues(resource, method, status).Inc()
+//		},
+//	}
+type MetricsInstrumenter struct {
+	// CountRequest is called for every attempt with the status code of the
+	// response, or "error" when no response was received.
+	CountRequest func(resource, method, status string)
+
+	// ObserveLatency is called with the duration of every attempt.
+	ObserveLatency func(resource, method string, seconds float64)
+
+	// CountRetry is called for every attempt after the first.
+	CountRetry func(resource, method string)
+
+	// ObserveThrottleWait is called with the time spent waiting on rate
+	// limits and backoff before an attempt, if any. GraphQL cost throttling
+	// is reported with the "graphql" resource.
+	ObserveThrottleWait func(resource string, seconds float64)
+
+	// SetBucketUsage is called with the REST bucket usage of every response.
+	SetBucketUsage func(requestCount, bucketSize float64)
+
+	// ObserveGraphQLCost is called with the requested and actual cost of
+	// every GraphQL query attempt that reported its cost.
+	ObserveGraphQLCost func(operation string, requested, actual float64)
+
+	// SetGraphQLAvailable is called with the points left in the GraphQL
+	// bucket after every query attempt that reported its cost.
+	SetGraphQLAvailable func(points float64)
+}
+
+// RequestStart implements Instrumenter.
+func (m *MetricsInstrumenter) RequestStart(ctx context.Context, event RequestEvent) context.Context {
+	if event.Attempt > 1 && m.CountRetry != nil {
+		m.CountRetry(event.Resource, event.Method)
+	}
+	if event.ThrottleWait > 0 && m.ObserveThrottleWait != nil {
+		m.ObserveThrottleWait(event.Resource, event.ThrottleWait.Seconds())
+	}
+	return ctx
+}
+
+// RequestEnd implements Instrumenter.
+func (m *MetricsInstrumenter) RequestEnd(ctx context.Context, event RequestEvent) {
+	if m.CountRequest != nil {
+		status := "error"
+		if event.StatusCode != 0 {
+			status = strconv.Itoa(event.StatusCode)
+		}
+		m.CountRequest(event.Resource, event.Method, status)
+	}
+	if m.ObserveLatency != nil {
+		m.ObserveLatency(event.Resource, event.Method, event.Duration.Seconds())
+	}
+	if event.BucketSize > 0 && m.SetBucketUsage != nil {
+		m.SetBucketUsage(float64(event.RequestCount), float64(event.BucketSize))
+	}
+}
+
+// GraphQLStart implements Instrumenter.
+func (m *MetricsInstrumenter) GraphQLStart(ctx context.Context, event GraphQLEvent) context.Context {
+	if event.ThrottleWait > 0 && m.ObserveThrottleWait != nil {
+		m.ObserveThrottleWait("graphql", event.ThrottleWait.Seconds())
+	}
+	return ctx
+}
+
+// GraphQLEnd implements Instrumenter.
+func (m *MetricsInstrumenter) GraphQLEnd(ctx context.Context, event GraphQLEvent) {
+	if event.Cost == nil {
+		return
+	}
+
+	if m.ObserveGraphQLCost != nil {
+		// the actual cost is missing when the query was throttled
+		var actual float64
+		if event.Cost.ActualQueryCost != nil {
+			actual = float64(*event.Cost.ActualQueryCost)
+		}
+		m.ObserveGraphQLCost(event.Operation, float64(event.Cost.RequestedQueryCost), actual)
+	}
+	if m.SetGraphQLAvailable != nil {
+		m.SetGraphQLAvailable(event.Cost.ThrottleStatus.CurrentlyAvailable)
+	}
+}
+
+// Span is the subset of an OpenTelemetry style span used by
+// TracingInstrumenter.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans for TracingInstrumenter. It is typically a thin wrapper
+// around an OpenTelemetry tracer.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// TracingInstrumenter is an Instrumenter recording a span for every request
+// attempt and GraphQL query attempt. Request spans of a GraphQL query are
+// children of the query's span.
+type TracingInstrumenter struct {
+	Tracer Tracer
+}
+
+type spanContextKey struct{}
+
+// RequestStart implements Instrumenter.
+func (t *TracingInstrumenter) RequestStart(ctx context.Context, event RequestEvent) context.Context {
+	ctx, span := t.Tracer.Start(ctx, "shopify "+event.Resource)
+	span.SetAttribute("http.method", event.Method)
+	span.SetAttribute("url.path", event.Path)
+	span.SetAttribute("shopify.resource", event.Resource)
+	span.SetAttribute("shopify.attempt", event.Attempt)
+	span.SetAttribute("shopify.throttle_wait_ms", event.ThrottleWait.Milliseconds())
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// RequestEnd implements Instrumenter.
+func (t *TracingInstrumenter) RequestEnd(ctx context.Context, event RequestEvent) {
+	span, ok := ctx.Value(spanContextKey{}).(Span)
+	if !ok {
+		return
+	}
+
+	if event.StatusCode != 0 {
+		span.SetAttribute("http.status_code", event.StatusCode)
+	}
+	if event.RequestId != "" {
+		span.SetAttribute("shopify.request_id", event.RequestId)
+	}
+	if event.BucketSize > 0 {
+		span.SetAttribute("shopify.bucket.request_count", event.RequestCount)
+		span.SetAttribute("shopify.bucket.size", event.BucketSize)
+	}
+	if event.Err != nil {
+		span.RecordError(event.Err)
+	}
+	span.End()
+}
+
+// GraphQLStart implements Instrumenter.
+func (t *TracingInstrumenter) GraphQLStart(ctx context.Context, event GraphQLEvent) context.Context {
+	ctx, span := t.Tracer.Start(ctx, "shopify graphql "+event.Operation)
+	span.SetAttribute("graphql.operation.name", event.Operation)
+	span.SetAttribute("shopify.attempt", event.Attempt)
+	span.SetAttribute("shopify.throttle_wait_ms", event.ThrottleWait.Milliseconds())
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// GraphQLEnd implements Instrumenter.
+func (t *TracingInstrumenter) GraphQLEnd(ctx context.Context, event GraphQLEvent) {
+	span, ok := ctx.Value(spanContextKey{}).(Span)
+	if !ok {
+		return
+	}
+
+	if event.Cost != nil {
+		span.SetAttribute("shopify.graphql.requested_cost", event.Cost.RequestedQueryCost)
+		if event.Cost.ActualQueryCost != nil {
+			span.SetAttribute("shopify.graphql.actual_cost", *event.Cost.ActualQueryCost)
+		}
+		span.SetAttribute("shopify.graphql.available", event.Cost.ThrottleStatus.CurrentlyAvailable)
+	}
+	if event.Err != nil {
+		span.RecordError(event.Err)
+	}
+	span.End()
+}
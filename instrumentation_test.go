@@ -0,0 +1,349 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// recordingInstrumenter keeps every event it receives
+type recordingInstrumenter struct {
+	mu       sync.Mutex
+	starts   []RequestEvent
+	ends     []RequestEvent
+	gqlStart []GraphQLEvent
+	gqlEnd   []GraphQLEvent
+}
+
+func (r *recordingInstrumenter) RequestStart(ctx context.Context, event RequestEvent) context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.starts = append(r.starts, event)
+	return ctx
+}
+
+func (r *recordingInstrumenter) RequestEnd(ctx context.Context, event RequestEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ends = append(r.ends, event)
+}
+
+func (r *recordingInstrumenter) GraphQLStart(ctx context.Context, event GraphQLEvent) context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gqlStart = append(r.gqlStart, event)
+	return ctx
+}
+
+func (r *recordingInstrumenter) GraphQLEnd(ctx context.Context, event GraphQLEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gqlEnd = append(r.gqlEnd, event)
+}
+
+func TestResourceName(t *testing.T) {
+	cases := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{"GET", "/admin/api/2024-01/orders.json", "orders.list"},
+		{"GET", "/admin/api/2024-01/orders/1.json", "orders.get"},
+		{"GET", "/admin/orders/count.json", "orders.count"},
+		{"POST", "/admin/api/2024-01/products/1/images.json", "products.images.create"},
+		{"PUT", "/admin/api/2024-01/products/1/images/2.json", "products.images.update"},
+		{"DELETE", "/admin/api/unstable/webhooks/3.json", "webhooks.delete"},
+		{"POST", "/admin/api/2024-01/graphql.json", "graphql"},
+		{"POST", "/admin/oauth/access_token", "oauth.access_token.create"},
+		{"GET", "/", "get"},
+	}
+
+	for _, c := range cases {
+		if actual := resourceName(c.method, c.path); actual != c.expected {
+			t.Errorf("resourceName(%s, %s) = %s, expected %s", c.method, c.path, actual, c.expected)
+		}
+	}
+}
+
+func TestGraphQLOperationName(t *testing.T) {
+	cases := map[string]string{
+		"query products($first: Int) { products { id } }": "products",
+		"  mutation productUpdate { id }":                 "productUpdate",
+		"query { shop { name } }":                         "anonymous",
+		"{ shop { name } }":                               "anonymous",
+	}
+
+	for q, expected := range cases {
+		if actual := graphQLOperationName(q); actual != expected {
+			t.Errorf("graphQLOperationName(%q) = %s, expected %s", q, actual, expected)
+		}
+	}
+}
+
+func TestInstrumenterRequestEvents(t *testing.T) {
+	setup()
+	defer teardown()
+
+	recorder := &recordingInstrumenter{}
+	WithInstrumenter(recorder)(client)
+	WithRetryPolicy(&DefaultRetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})(client)
+
+	calls := 0
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders/count.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), nil
+			}
+			resp := httpmock.NewStringResponse(200, `{"count": 7}`)
+			resp.Header.Set("X-Shopify-Shop-Api-Call-Limit", "3/40")
+			resp.Header.Set("X-Request-Id", "req-1")
+			return resp, nil
+		})
+
+	if _, err := client.Order.Count(context.Background(), nil); err != nil {
+		t.Fatalf("Order.Count returned error: %v", err)
+	}
+
+	if len(recorder.starts) != 2 || len(recorder.ends) != 2 {
+		t.Fatalf("expected 2 start and end events, got %d and %d", len(recorder.starts), len(recorder.ends))
+	}
+
+	for i, event := range recorder.starts {
+		if event.Resource != "orders.count" || event.Method != "GET" || event.Attempt != i+1 {
+			t.Errorf("start event %d = %#v", i, event)
+		}
+	}
+
+	failed := recorder.ends[0]
+	if failed.StatusCode != http.StatusServiceUnavailable || failed.Err == nil {
+		t.Errorf("first end event = %#v, expected a 503 error", failed)
+	}
+
+	succeeded := recorder.ends[1]
+	if succeeded.StatusCode != 200 || succeeded.Err != nil || succeeded.RequestCount != 3 ||
+		succeeded.BucketSize != 40 || succeeded.RequestId != "req-1" {
+		t.Errorf("second end event = %#v, expected a successful response", succeeded)
+	}
+}
+
+// slowFirstLimiter makes the first request wait
+type slowFirstLimiter struct {
+	waits int
+}
+
+func (l *slowFirstLimiter) Wait(ctx context.Context) error {
+	l.waits++
+	if l.waits == 1 {
+		time.Sleep(50 * time.Millisecond)
+	}
+	return nil
+}
+
+func (l *slowFirstLimiter) Observe(requestCount, bucketSize int) {}
+
+func TestInstrumenterThrottleWaitPerAttempt(t *testing.T) {
+	setup()
+	defer teardown()
+
+	recorder := &recordingInstrumenter{}
+	WithInstrumenter(recorder)(client)
+	WithRateLimiter(&slowFirstLimiter{})(client)
+	WithRetryPolicy(&DefaultRetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})(client)
+
+	calls := 0
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders/count.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), nil
+			}
+			return httpmock.NewStringResponse(200, `{"count": 7}`), nil
+		})
+
+	if _, err := client.Order.Count(context.Background(), nil); err != nil {
+		t.Fatalf("Order.Count returned error: %v", err)
+	}
+	if len(recorder.starts) != 2 {
+		t.Fatalf("expected 2 start events, got %d", len(recorder.starts))
+	}
+
+	// the rate limiter wait of the first attempt isn't reported again by the retry
+	if wait := recorder.starts[0].ThrottleWait; wait < 50*time.Millisecond {
+		t.Errorf("first attempt waited %s, expected at least 50ms", wait)
+	}
+	if wait := recorder.starts[1].ThrottleWait; wait <= 0 || wait >= 50*time.Millisecond {
+		t.Errorf("retry waited %s, expected its backoff only", wait)
+	}
+}
+
+func TestInstrumenterGraphQLEvents(t *testing.T) {
+	setup()
+	defer teardown()
+
+	recorder := &recordingInstrumenter{}
+	WithInstrumenter(recorder)(client)
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{},"extensions":{"cost":{"requestedQueryCost":10,"actualQueryCost":4,"throttleStatus":{"maximumAvailable":1000,"currentlyAvailable":996,"restoreRate":50}}}}`))
+
+	if err := client.GraphQL.Query(context.Background(), "query shop { shop { name } }", nil, nil); err != nil {
+		t.Fatalf("GraphQL.Query returned error: %v", err)
+	}
+
+	if len(recorder.gqlStart) != 1 || len(recorder.gqlEnd) != 1 {
+		t.Fatalf("expected 1 GraphQL start and end event, got %d and %d", len(recorder.gqlStart), len(recorder.gqlEnd))
+	}
+
+	event := recorder.gqlEnd[0]
+	if event.Operation != "shop" || event.Attempt != 1 || event.Cost == nil || *event.Cost.ActualQueryCost != 4 {
+		t.Errorf("GraphQL end event = %#v", event)
+	}
+
+	// the http request is reported too
+	if len(recorder.ends) != 1 || recorder.ends[0].Resource != "graphql" {
+		t.Errorf("expected a graphql request event, got %#v", recorder.ends)
+	}
+}
+
+func TestMetricsInstrumenter(t *testing.T) {
+	var recorded []string
+	record := func(format string, v ...interface{}) {
+		recorded = append(recorded, fmt.Sprintf(format, v...))
+	}
+
+	m := &MetricsInstrumenter{
+		CountRequest:        func(resource, method, status string) { record("request %s %s %s", resource, method, status) },
+		ObserveLatency:      func(resource, method string, seconds float64) { record("latency %s %s %v", resource, method, seconds) },
+		CountRetry:          func(resource, method string) { record("retry %s %s", resource, method) },
+		ObserveThrottleWait: func(resource string, seconds float64) { record("wait %s %v", resource, seconds) },
+		SetBucketUsage:      func(count, size float64) { record("bucket %v/%v", count, size) },
+		ObserveGraphQLCost: func(operation string, requested, actual float64) {
+			record("cost %s %v %v", operation, requested, actual)
+		},
+		SetGraphQLAvailable: func(points float64) { record("available %v", points) },
+	}
+
+	ctx := context.Background()
+	event := RequestEvent{Resource: "orders.list", Method: "GET", Attempt: 2, ThrottleWait: time.Second}
+	m.RequestStart(ctx, event)
+	event.StatusCode = 200
+	event.Duration = 2 * time.Second
+	event.RequestCount = 1
+	event.BucketSize = 40
+	m.RequestEnd(ctx, event)
+	m.RequestEnd(ctx, RequestEvent{Resource: "orders.list", Method: "GET", Err: errors.New("timeout")})
+
+	gqlEvent := GraphQLEvent{Operation: "shop", ThrottleWait: time.Second}
+	m.GraphQLStart(ctx, gqlEvent)
+	gqlEvent.Cost = &GraphQLCost{
+		RequestedQueryCost: 10,
+		ActualQueryCost:    makeIntPointer(4),
+		ThrottleStatus:     GraphQLThrottleStatus{CurrentlyAvailable: 996},
+	}
+	m.GraphQLEnd(ctx, gqlEvent)
+
+	expected := []string{
+		"retry orders.list GET",
+		"wait orders.list 1",
+		"request orders.list GET 200",
+		"latency orders.list GET 2",
+		"bucket 1/40",
+		"request orders.list GET error",
+		"latency orders.list GET 0",
+		"wait graphql 1",
+		"cost shop 10 4",
+		"available 996",
+	}
+	if !reflect.DeepEqual(recorded, expected) {
+		t.Errorf("MetricsInstrumenter recorded %#v, expected %#v", recorded, expected)
+	}
+
+	// unset fields are skipped
+	empty := &MetricsInstrumenter{}
+	empty.RequestStart(ctx, event)
+	empty.RequestEnd(ctx, event)
+	empty.GraphQLStart(ctx, gqlEvent)
+	empty.GraphQLEnd(ctx, gqlEvent)
+}
+
+// testSpan records the attributes and errors of a span
+type testSpan struct {
+	name       string
+	parent     *testSpan
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.err = err }
+func (s *testSpan) End()                                       { s.ended = true }
+
+type testParentKey struct{}
+
+// testTracer records every span it starts
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parent, _ := ctx.Value(testParentKey{}).(*testSpan)
+	span := &testSpan{name: name, parent: parent, attributes: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testParentKey{}, span), span
+}
+
+func TestTracingInstrumenter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tracer := &testTracer{}
+	WithInstrumenter(&TracingInstrumenter{Tracer: tracer})(client)
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"errors":[{"message":"oops"}],"extensions":{"cost":{"requestedQueryCost":10,"actualQueryCost":4,"throttleStatus":{"maximumAvailable":1000,"currentlyAvailable":996,"restoreRate":50}}}}`))
+
+	if err := client.GraphQL.Query(context.Background(), "query shop { shop { name } }", nil, nil); err == nil {
+		t.Fatal("GraphQL.Query should return error!")
+	}
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(tracer.spans))
+	}
+
+	query, request := tracer.spans[0], tracer.spans[1]
+	if query.name != "shopify graphql shop" || !query.ended || query.err == nil ||
+		query.attributes["shopify.graphql.actual_cost"] != 4 {
+		t.Errorf("unexpected query span %#v", query)
+	}
+	if request.name != "shopify graphql" || !request.ended || request.parent != query ||
+		request.attributes["http.status_code"] != 200 {
+		t.Errorf("unexpected request span %#v", request)
+	}
+}
+
+func TestMultiInstrumenter(t *testing.T) {
+	first, second := &recordingInstrumenter{}, &recordingInstrumenter{}
+	m := MultiInstrumenter(first, second)
+
+	ctx := context.Background()
+	m.RequestEnd(m.RequestStart(ctx, RequestEvent{}), RequestEvent{})
+	m.GraphQLEnd(m.GraphQLStart(ctx, GraphQLEvent{}), GraphQLEvent{})
+
+	for _, r := range []*recordingInstrumenter{first, second} {
+		if len(r.starts) != 1 || len(r.ends) != 1 || len(r.gqlStart) != 1 || len(r.gqlEnd) != 1 {
+			t.Errorf("MultiInstrumenter did not forward every event: %#v", r)
+		}
+	}
+}
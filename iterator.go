@@ -0,0 +1,160 @@
+package goshopify
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+)
+
+// ListFunc lists a page of resources, such as ProductService.ListWithPagination.
+type ListFunc[T any] func(ctx context.Context, options interface{}) ([]T, *Pagination, error)
+
+// Iterator walks every resource of a paginated list endpoint, requesting the
+// next page when the current one is exhausted:
+//
+//	it := client.Order.Iterate(&goshopify.OrderListOptions{Status: "any"})
+//	for it.Next(ctx) {
+//		order := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+//
+// Shopify rejects filters alongside page_info, so pages after the first are
+// requested with the page_info, limit and fields only. An Iterator is not safe
+// for concurrent use.
+type Iterator[T any] struct {
+	list    ListFunc[T]
+	options interface{}
+
+	// fields of the first request, kept on every page
+	fields string
+
+	page  []T
+	index int
+	value T
+
+	// page_info of the current page and options of the next one
+	cursor string
+	next   *ListOptions
+
+	started bool
+	err     error
+}
+
+// NewIterator returns an Iterator listing pages with list, starting with
+// options. Options with a page_info, e.g. a ListOptions with a Cursor of a
+// previous iterator, resume from that page.
+func NewIterator[T any](list ListFunc[T], options interface{}) *Iterator[T] {
+	return &Iterator[T]{list: list, options: options}
+}
+
+// Next advances to the next resource, requesting the next page when needed.
+// It returns false when every resource was listed or a request failed, see
+// Err.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for it.index >= len(it.page) {
+		if it.err != nil || (it.started && it.next == nil) {
+			return false
+		}
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.value = it.page[it.index]
+	it.index++
+	return true
+}
+
+// fetch requests the first or next page.
+func (it *Iterator[T]) fetch(ctx context.Context) error {
+	options := it.options
+	if it.started {
+		options = it.next
+	} else {
+		first, err := it.firstPageOptions()
+		if err != nil {
+			return err
+		}
+		if first != nil {
+			options = first
+		}
+	}
+
+	page, pagination, err := it.list(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	it.cursor = ""
+	if listOptions, ok := options.(*ListOptions); ok && listOptions != nil {
+		it.cursor = listOptions.PageInfo
+	}
+
+	it.started = true
+	it.page, it.index = page, 0
+	it.next = nil
+	if pagination != nil && pagination.NextPageOptions != nil {
+		next := *pagination.NextPageOptions
+		next.Fields = it.fields
+		it.next = &next
+	}
+	return nil
+}
+
+// firstPageOptions reads the fields of the options and, when they hold a
+// page_info, returns the options to request with without the filters.
+func (it *Iterator[T]) firstPageOptions() (*ListOptions, error) {
+	if it.options == nil {
+		return nil, nil
+	}
+
+	values, err := query.Values(it.options)
+	if err != nil {
+		return nil, err
+	}
+
+	it.fields = values.Get("fields")
+
+	pageInfo := values.Get("page_info")
+	if pageInfo == "" {
+		return nil, nil
+	}
+
+	options := &ListOptions{PageInfo: pageInfo, Fields: it.fields}
+	if limit := values.Get("limit"); limit != "" {
+		if options.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
+
+// Value returns the current resource.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Cursor returns the page_info of the page the current resource belongs to,
+// empty for the first page. Listing again from a ListOptions with this
+// PageInfo resumes with that page.
+func (it *Iterator[T]) Cursor() string {
+	return it.cursor
+}
+
+// NextCursor returns the page_info of the page after the current one, empty
+// when the current page is the last one.
+func (it *Iterator[T]) NextCursor() string {
+	if it.next == nil {
+		return ""
+	}
+	return it.next.PageInfo
+}
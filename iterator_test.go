@@ -0,0 +1,190 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// fakePages serves pages of ids keyed by page_info, "" being the first page
+type fakePages struct {
+	pages    map[string][]uint64
+	next     map[string]string
+	requests []interface{}
+	err      error
+}
+
+func (f *fakePages) list(_ context.Context, options interface{}) ([]uint64, *Pagination, error) {
+	f.requests = append(f.requests, options)
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+
+	pageInfo := ""
+	if listOptions, ok := options.(*ListOptions); ok {
+		pageInfo = listOptions.PageInfo
+	}
+
+	pagination := new(Pagination)
+	if next, ok := f.next[pageInfo]; ok {
+		pagination.NextPageOptions = &ListOptions{PageInfo: next, Limit: 2}
+	}
+	return f.pages[pageInfo], pagination, nil
+}
+
+func collectIds(it *Iterator[uint64]) []uint64 {
+	var ids []uint64
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Value())
+	}
+	return ids
+}
+
+func TestIterator(t *testing.T) {
+	pages := &fakePages{
+		pages: map[string][]uint64{"": {1, 2}, "pg2": {}, "pg3": {3}},
+		next:  map[string]string{"": "pg2", "pg2": "pg3"},
+	}
+
+	options := &OrderListOptions{Status: OrderStatusAny, ListOptions: ListOptions{Limit: 2, Fields: "id,name"}}
+	it := NewIterator(pages.list, options)
+
+	ids := collectIds(it)
+	if it.Err() != nil {
+		t.Fatalf("Iterator.Err() = %v", it.Err())
+	}
+	if !reflect.DeepEqual(ids, []uint64{1, 2, 3}) {
+		t.Errorf("Iterator listed %v, expected [1 2 3]", ids)
+	}
+
+	// filters are only sent with the first page, fields are kept
+	expected := []interface{}{
+		options,
+		&ListOptions{PageInfo: "pg2", Limit: 2, Fields: "id,name"},
+		&ListOptions{PageInfo: "pg3", Limit: 2, Fields: "id,name"},
+	}
+	if !reflect.DeepEqual(pages.requests, expected) {
+		t.Errorf("Iterator requested %#v, expected %#v", pages.requests, expected)
+	}
+
+	if it.Cursor() != "pg3" || it.NextCursor() != "" {
+		t.Errorf("Iterator cursors = %q, %q, expected pg3 and none", it.Cursor(), it.NextCursor())
+	}
+
+	// exhausted iterators don't request again
+	if it.Next(context.Background()) || len(pages.requests) != 3 {
+		t.Error("Iterator.Next() should return false once exhausted")
+	}
+}
+
+func TestIteratorCursor(t *testing.T) {
+	pages := &fakePages{
+		pages: map[string][]uint64{"": {1, 2}, "pg2": {3}},
+		next:  map[string]string{"": "pg2"},
+	}
+
+	it := NewIterator(pages.list, nil)
+	if !it.Next(context.Background()) {
+		t.Fatal("Iterator.Next() = false, expected true")
+	}
+	if it.Cursor() != "" || it.NextCursor() != "pg2" {
+		t.Errorf("Iterator cursors = %q, %q, expected none and pg2", it.Cursor(), it.NextCursor())
+	}
+
+	it.Next(context.Background())
+	it.Next(context.Background())
+	if it.Value() != 3 || it.Cursor() != "pg2" {
+		t.Errorf("Iterator value %d with cursor %q, expected 3 with pg2", it.Value(), it.Cursor())
+	}
+}
+
+func TestIteratorResume(t *testing.T) {
+	pages := &fakePages{
+		pages: map[string][]uint64{"pg2": {3}},
+	}
+
+	// filters are dropped when resuming from a page_info
+	options := &OrderListOptions{
+		Status:      OrderStatusAny,
+		ListOptions: ListOptions{PageInfo: "pg2", Limit: 2, Fields: "id", Vendor: "acme"},
+	}
+	ids := collectIds(NewIterator(pages.list, options))
+
+	if !reflect.DeepEqual(ids, []uint64{3}) {
+		t.Errorf("Iterator listed %v, expected [3]", ids)
+	}
+	expected := []interface{}{&ListOptions{PageInfo: "pg2", Limit: 2, Fields: "id"}}
+	if !reflect.DeepEqual(pages.requests, expected) {
+		t.Errorf("Iterator requested %#v, expected %#v", pages.requests, expected)
+	}
+}
+
+func TestIteratorError(t *testing.T) {
+	pages := &fakePages{err: errors.New("boom")}
+	it := NewIterator(pages.list, nil)
+
+	if it.Next(context.Background()) {
+		t.Error("Iterator.Next() = true, expected false")
+	}
+	if it.Err() != pages.err {
+		t.Errorf("Iterator.Err() = %v, expected %v", it.Err(), pages.err)
+	}
+
+	// invalid options
+	it = NewIterator(pages.list, "invalid")
+	if it.Next(context.Background()) || it.Err() == nil {
+		t.Error("Iterator should fail with options that aren't a struct")
+	}
+}
+
+func TestOrderIterate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/orders.json", client.pathPrefix)
+
+	httpmock.RegisterResponderWithQuery("GET", listURL, "status=any&limit=1",
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, `{"orders": [{"id":1}]}`)
+			resp.Header.Set("Link", fmt.Sprintf(`<%s?page_info=pg2&limit=1>; rel="next"`, listURL))
+			return resp, nil
+		})
+	httpmock.RegisterResponderWithQuery("GET", listURL, "page_info=pg2&limit=1",
+		httpmock.NewStringResponder(200, `{"orders": [{"id":2}]}`))
+
+	it := client.Order.Iterate(&OrderListOptions{Status: OrderStatusAny, ListOptions: ListOptions{Limit: 1}})
+
+	var ids []uint64
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Value().Id)
+	}
+	if it.Err() != nil {
+		t.Fatalf("Order.Iterate returned error: %v", it.Err())
+	}
+	if !reflect.DeepEqual(ids, []uint64{1, 2}) {
+		t.Errorf("Order.Iterate listed %v, expected [1 2]", ids)
+	}
+}
+
+func TestCollectionIterateProducts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/collections/1/products.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"products": [{"id":1},{"id":2}]}`))
+
+	it := client.Collection.IterateProducts(1, nil)
+
+	count := 0
+	for it.Next(context.Background()) {
+		count++
+	}
+	if it.Err() != nil || count != 2 {
+		t.Errorf("Collection.IterateProducts listed %d products with error %v, expected 2", count, it.Err())
+	}
+}
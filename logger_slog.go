@@ -0,0 +1,94 @@
+//go:build go1.21
+
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// SlogLogger writes the client's logs to a log/slog Handler. It is both a
+// StructuredLogger, for request and response entries with typed attributes,
+// and a LeveledLoggerInterface for the client's other messages:
+//
+//	logger := goshopify.NewSlogLogger(slog.NewJSONHandler(os.Stdout, nil))
+//	client, err := goshopify.NewClient(app, "shopname", "token",
+//		goshopify.WithLogger(logger),
+//		goshopify.WithStructuredLogger(logger))
+type SlogLogger struct {
+	handler slog.Handler
+}
+
+// NewSlogLogger returns a SlogLogger writing to the handler.
+func NewSlogLogger(handler slog.Handler) *SlogLogger {
+	return &SlogLogger{handler: handler}
+}
+
+// Enabled implements StructuredLogger.
+func (l *SlogLogger) Enabled(ctx context.Context, level int) bool {
+	return l.handler.Enabled(ctx, slogLevel(level))
+}
+
+// Log implements StructuredLogger.
+func (l *SlogLogger) Log(ctx context.Context, level int, msg string, attrs ...LogAttr) {
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:]) // skip runtime.Callers and Log
+	record := slog.NewRecord(time.Now(), slogLevel(level), msg, pcs[0])
+	for _, attr := range attrs {
+		record.AddAttrs(slog.Any(attr.Key, attr.Value))
+	}
+	_ = l.handler.Handle(ctx, record)
+}
+
+// Debugf logs a debug message using Printf conventions.
+func (l *SlogLogger) Debugf(format string, v ...interface{}) {
+	l.logf(LevelDebug, format, v...)
+}
+
+// Errorf logs an error message using Printf conventions.
+func (l *SlogLogger) Errorf(format string, v ...interface{}) {
+	l.logf(LevelError, format, v...)
+}
+
+// Infof logs an informational message using Printf conventions.
+func (l *SlogLogger) Infof(format string, v ...interface{}) {
+	l.logf(LevelInfo, format, v...)
+}
+
+// Warnf logs a warning message using Printf conventions.
+func (l *SlogLogger) Warnf(format string, v ...interface{}) {
+	l.logf(LevelWarn, format, v...)
+}
+
+func (l *SlogLogger) logf(level int, format string, v ...interface{}) {
+	ctx := context.Background()
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip runtime.Callers, logf and the Xf method
+	record := slog.NewRecord(time.Now(), slogLevel(level), fmt.Sprintf(format, v...), pcs[0])
+	_ = l.handler.Handle(ctx, record)
+}
+
+// slogLevel converts a LeveledLogger level to a slog.Level.
+func slogLevel(level int) slog.Level {
+	switch level {
+	case LevelError:
+		return slog.LevelError
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelInfo:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
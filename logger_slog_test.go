@@ -0,0 +1,85 @@
+//go:build go1.21
+
+package goshopify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestSlogLogger(t *testing.T) {
+	setup()
+	defer teardown()
+
+	out := &bytes.Buffer{}
+	logger := NewSlogLogger(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	WithLogger(logger)(client)
+	WithStructuredLogger(logger)(client)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shop.json", client.pathPrefix),
+		createResponderWithHeaders(200, `{"shop":{"id":1}}`, map[string]string{
+			"X-Request-Id": "req-1",
+		}))
+
+	if _, err := client.Shop.Get(context.Background(), nil); err != nil {
+		t.Fatalf("Shop.Get returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %q", out.String())
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &response); err != nil {
+		t.Fatalf("could not decode log line %s: %v", lines[1], err)
+	}
+
+	expected := map[string]interface{}{
+		"level":      "DEBUG",
+		"msg":        "shopify response",
+		"shop":       "fooshop.myshopify.com",
+		"method":     "GET",
+		"status":     float64(200),
+		"request_id": "req-1",
+	}
+	for k, v := range expected {
+		if response[k] != v {
+			t.Errorf("log attribute %s = %#v, expected %#v", k, response[k], v)
+		}
+	}
+
+	out.Reset()
+	logger.Warnf("retrying %d", 2)
+	if !strings.Contains(out.String(), `"level":"WARN","msg":"retrying 2"`) {
+		t.Errorf("Warnf wrote %s", out.String())
+	}
+}
+
+func TestSlogLoggerLevels(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger := NewSlogLogger(slog.NewTextHandler(out, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	if logger.Enabled(context.Background(), LevelInfo) || !logger.Enabled(context.Background(), LevelError) {
+		t.Errorf("SlogLogger levels do not match the handler level")
+	}
+
+	logger.Debugf("debug")
+	logger.Infof("info")
+	logger.Log(context.Background(), LevelDebug, "debug")
+	if out.Len() != 0 {
+		t.Errorf("SlogLogger wrote disabled levels: %s", out.String())
+	}
+
+	logger.Errorf("error")
+	if !strings.Contains(out.String(), "level=ERROR msg=error") {
+		t.Errorf("Errorf wrote %s", out.String())
+	}
+}
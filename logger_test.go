@@ -2,12 +2,16 @@ package goshopify
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/jarcoal/httpmock"
 )
 
 func TestLeveledLogger(t *testing.T) {
@@ -123,3 +127,29 @@ func TestDoGetHeadersDebug(t *testing.T) {
 		t.Errorf("doGetHeadersDebug expected stdout \"%s\" received \"%s\"", resExpected, out.String())
 	}
 }
+
+func TestStreamedResponseBodyNotLogged(t *testing.T) {
+	setup()
+	defer teardown()
+
+	out := &bytes.Buffer{}
+	client.log = &LeveledLogger{Level: LevelDebug, stderrOverride: out, stdoutOverride: out}
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"orders": [{"id":1}]}`))
+
+	if _, err := client.Order.StreamWithPagination(context.Background(), nil, func(Order) error { return nil }); err != nil {
+		t.Fatalf("Order.StreamWithPagination returned error: %v", err)
+	}
+	if strings.Contains(out.String(), "RESP:") || !strings.Contains(out.String(), "RECV 200") {
+		t.Errorf("streamed response logged %q, expected no body", out.String())
+	}
+
+	out.Reset()
+	if _, _, err := client.Order.ListWithPagination(context.Background(), nil); err != nil {
+		t.Fatalf("Order.ListWithPagination returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), `RESP: {"orders":[{"id":1}]}`) {
+		t.Errorf("listed response logged %q, expected its body", out.String())
+	}
+}
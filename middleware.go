@@ -0,0 +1,28 @@
+package goshopify
+
+import "net/http"
+
+// RequestHandler sends a request to Shopify and decodes the response body into
+// v. It returns the response headers, or the error decoded from the response
+// such as a ResponseError or RateLimitError.
+type RequestHandler func(req *http.Request, v interface{}) (http.Header, error)
+
+// Middleware wraps the handling of a request, see WithMiddleware. The request
+// URL holds the resolved resource path, e.g. "/admin/api/2024-01/orders.json",
+// and the request is sent to Shopify, including any retries, when next is
+// called. Middleware may change the request before calling next, inspect the
+// headers and error it returns, or return without calling next at all.
+//
+// For example, to add a tracing header and log failed requests:
+//
+//	func tracing(next goshopify.RequestHandler) goshopify.RequestHandler {
+//		return func(req *http.Request, v interface{}) (http.Header, error) {
+//			req.Header.Set("X-Trace-Id", traceID(req.Context()))
+//			headers, err := next(req, v)
+//			if err != nil {
+//				log.Printf("%s %s failed: %v", req.Method, req.URL.Path, err)
+//			}
+//			return headers, err
+//		}
+//	}
+type Middleware func(next RequestHandler) RequestHandler
@@ -0,0 +1,134 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// recordingMiddleware records the method, path and error of every request
+type recordingMiddleware struct {
+	name  string
+	calls *[]string
+}
+
+func (m recordingMiddleware) wrap(next RequestHandler) RequestHandler {
+	return func(req *http.Request, v interface{}) (http.Header, error) {
+		*m.calls = append(*m.calls, fmt.Sprintf("%s before %s %s", m.name, req.Method, req.URL.Path))
+		headers, err := next(req, v)
+		*m.calls = append(*m.calls, fmt.Sprintf("%s after %v", m.name, err))
+		return headers, err
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls []string
+	WithMiddleware(
+		recordingMiddleware{"outer", &calls}.wrap,
+		recordingMiddleware{"inner", &calls}.wrap,
+	)(client)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/products/1.json", client.pathPrefix),
+		httpmock.NewStringResponder(404, `{"errors": "Not Found"}`))
+
+	_, err := client.Product.Get(context.Background(), 1, nil)
+	expectedErr := ResponseError{Status: 404, Message: "Not Found"}
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Product.Get returned error %#v, expected %#v", err, expectedErr)
+	}
+
+	expected := []string{
+		fmt.Sprintf("outer before GET /%s/products/1.json", client.pathPrefix),
+		fmt.Sprintf("inner before GET /%s/products/1.json", client.pathPrefix),
+		"inner after Not Found",
+		"outer after Not Found",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("middleware calls = %#v, expected %#v", calls, expected)
+	}
+}
+
+func TestMiddlewareModifiesRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	WithMiddleware(func(next RequestHandler) RequestHandler {
+		return func(req *http.Request, v interface{}) (http.Header, error) {
+			req.Header.Set("X-Trace-Id", "trace-1")
+			return next(req, v)
+		}
+	})(client)
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("X-Trace-Id") != "trace-1" {
+				return httpmock.NewStringResponse(400, `{"errors": "missing trace"}`), nil
+			}
+			return httpmock.NewStringResponse(200, `{"data":{"foo":"bar"}}`), nil
+		})
+
+	resp := struct {
+		Foo string `json:"foo"`
+	}{}
+	if err := client.GraphQL.Query(context.Background(), "query {}", nil, &resp); err != nil {
+		t.Fatalf("GraphQL.Query returned error: %v", err)
+	}
+	if resp.Foo != "bar" {
+		t.Errorf("resp.Foo returned %s expected bar", resp.Foo)
+	}
+}
+
+func TestMiddlewareFaultInjection(t *testing.T) {
+	setup()
+	defer teardown()
+
+	injected := errors.New("injected")
+	WithMiddleware(func(next RequestHandler) RequestHandler {
+		return func(req *http.Request, v interface{}) (http.Header, error) {
+			return nil, injected
+		}
+	})(client)
+
+	_, err := client.Product.Count(context.Background(), nil)
+	if !errors.Is(err, injected) {
+		t.Errorf("Product.Count returned error %v, expected %v", err, injected)
+	}
+
+	if calls := httpmock.GetTotalCallCount(); calls != 0 {
+		t.Errorf("expected no requests to be sent, %d were", calls)
+	}
+}
+
+func TestMiddlewareAccessToken(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var paths []string
+	app.Client = client
+	WithMiddleware(func(next RequestHandler) RequestHandler {
+		return func(req *http.Request, v interface{}) (http.Header, error) {
+			paths = append(paths, req.URL.Path)
+			return next(req, v)
+		}
+	})(client)
+
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/admin/oauth/access_token",
+		httpmock.NewStringResponder(200, `{"access_token":"footoken"}`))
+
+	if _, err := app.GetAccessToken(context.Background(), "fooshop", "foo"); err != nil {
+		t.Fatalf("App.GetAccessToken returned error: %v", err)
+	}
+
+	if len(paths) != 1 || !strings.HasSuffix(paths[0], "oauth/access_token") {
+		t.Errorf("middleware saw paths %v, expected the access token path", paths)
+	}
+}
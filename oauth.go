@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"sort"
 	"strings"
+	"time"
 )
 
 const shopifyChecksumHeader = "X-Shopify-Hmac-Sha256"
@@ -25,6 +26,18 @@ var accessTokenRelPath = "admin/oauth/access_token"
 // State is a unique value that can be used to check the authenticity during a
 // callback from Shopify.
 func (app App) AuthorizeUrl(shopName string, state string) (string, error) {
+	return app.authorizeUrl(shopName, state, false)
+}
+
+// Returns a Shopify oauth authorization url for an online access token, tied
+// to the user authorizing the app and expiring with their session, see
+// AuthorizeUrl. Exchange the code with GetAccessTokenResponse to get the
+// associated user.
+func (app App) AuthorizeOnlineUrl(shopName string, state string) (string, error) {
+	return app.authorizeUrl(shopName, state, true)
+}
+
+func (app App) authorizeUrl(shopName string, state string, perUser bool) (string, error) {
 	shopUrl, err := url.Parse(ShopBaseUrl(shopName))
 	if err != nil {
 		return "", err
@@ -35,15 +48,69 @@ func (app App) AuthorizeUrl(shopName string, state string) (string, error) {
 	query.Set("redirect_uri", app.RedirectUrl)
 	query.Set("scope", app.Scope)
 	query.Set("state", state)
+	if perUser {
+		query.Set("grant_options[]", "per-user")
+	}
 	shopUrl.RawQuery = query.Encode()
 	return shopUrl.String(), nil
 }
 
+// accessTokenExpiryMargin is how long before its expiry an online access
+// token must be refreshed, so that it doesn't expire during a request
+const accessTokenExpiryMargin = time.Minute
+
+// AccessTokenResponse is the access token granted for an authorization code.
+// Online access tokens expire and are associated with the user who
+// authorized the app, offline access tokens don't.
+type AccessTokenResponse struct {
+	AccessToken         string           `json:"access_token"`
+	Scope               string           `json:"scope,omitempty"`
+	ExpiresIn           int              `json:"expires_in,omitempty"`
+	AssociatedUserScope string           `json:"associated_user_scope,omitempty"`
+	AssociatedUser      *AccessTokenUser `json:"associated_user,omitempty"`
+
+	// ExpiresAt is when an online access token expires, computed from
+	// ExpiresIn when the token is granted.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// AccessTokenUser is the user associated with an online access token
+type AccessTokenUser struct {
+	Id            uint64 `json:"id"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	AccountOwner  bool   `json:"account_owner"`
+	Locale        string `json:"locale"`
+	Collaborator  bool   `json:"collaborator"`
+}
+
+// Online reports whether the access token is an online access token.
+func (t AccessTokenResponse) Online() bool {
+	return t.ExpiresAt != nil || t.AssociatedUser != nil
+}
+
+// NeedsReauthorization reports whether an online access token expired, or
+// expires within a minute, at the time now. Online access tokens can't be
+// refreshed, the user must authorize the app again, see AuthorizeOnlineUrl.
+// Offline access tokens don't expire.
+func (t AccessTokenResponse) NeedsReauthorization(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Add(accessTokenExpiryMargin).Before(*t.ExpiresAt)
+}
+
 func (app App) GetAccessToken(ctx context.Context, shopName string, code string) (string, error) {
-	type Token struct {
-		Token string `json:"access_token"`
+	token, err := app.GetAccessTokenResponse(ctx, shopName, code)
+	if err != nil {
+		return "", err
 	}
+	return token.AccessToken, nil
+}
 
+// GetAccessTokenResponse exchanges an authorization code for an access
+// token, offline or online depending on the authorization url, see
+// AuthorizeUrl and AuthorizeOnlineUrl.
+func (app App) GetAccessTokenResponse(ctx context.Context, shopName string, code string) (*AccessTokenResponse, error) {
 	data := struct {
 		ClientId     string `json:"client_id"`
 		ClientSecret string `json:"client_secret"`
@@ -61,12 +128,20 @@ func (app App) GetAccessToken(ctx context.Context, shopName string, code string)
 
 	req, err := client.NewRequest(ctx, "POST", accessTokenRelPath, data, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	token := new(Token)
-	err = client.Do(req, token)
-	return token.Token, err
+	// the token expires in seconds from when it was requested
+	requestedAt := time.Now()
+	token := new(AccessTokenResponse)
+	if err := client.Do(req, token); err != nil {
+		return nil, err
+	}
+	if token.ExpiresIn > 0 {
+		expiresAt := requestedAt.Add(time.Duration(token.ExpiresIn) * time.Second)
+		token.ExpiresAt = &expiresAt
+	}
+	return token, nil
 }
 
 // Verify a message against a message HMAC
@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jarcoal/httpmock"
 )
@@ -67,6 +69,109 @@ func TestAppGetAccessToken(t *testing.T) {
 	}
 }
 
+func TestAppAuthorizeOnlineUrl(t *testing.T) {
+	setup()
+	defer teardown()
+
+	actual, err := app.AuthorizeOnlineUrl("fooshop", "thenonce")
+	if err != nil {
+		t.Fatalf("App.AuthorizeOnlineUrl(): %v", err)
+	}
+
+	expected := "https://fooshop.myshopify.com/admin/oauth/authorize?client_id=apikey&grant_options%5B%5D=per-user&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&scope=read_products&state=thenonce"
+	if actual != expected {
+		t.Errorf("App.AuthorizeOnlineUrl(): expected %s, actual %s", expected, actual)
+	}
+}
+
+func TestAppGetAccessTokenResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/admin/oauth/access_token",
+		httpmock.NewStringResponder(200, `{
+			"access_token":"onlinetoken",
+			"scope":"write_orders,read_customers",
+			"expires_in":86399,
+			"associated_user_scope":"write_orders",
+			"associated_user":{
+				"id":902541635,
+				"first_name":"John",
+				"last_name":"Smith",
+				"email":"john@example.com",
+				"email_verified":true,
+				"account_owner":true,
+				"locale":"en",
+				"collaborator":false
+			}
+		}`))
+
+	app.Client = client
+	start := time.Now()
+	token, err := app.GetAccessTokenResponse(context.Background(), "fooshop", "foocode")
+	if err != nil {
+		t.Fatalf("App.GetAccessTokenResponse(): %v", err)
+	}
+
+	expectedUser := &AccessTokenUser{
+		Id:            902541635,
+		FirstName:     "John",
+		LastName:      "Smith",
+		Email:         "john@example.com",
+		EmailVerified: true,
+		AccountOwner:  true,
+		Locale:        "en",
+	}
+	if token.AccessToken != "onlinetoken" || token.Scope != "write_orders,read_customers" || token.ExpiresIn != 86399 ||
+		token.AssociatedUserScope != "write_orders" || !reflect.DeepEqual(token.AssociatedUser, expectedUser) {
+		t.Errorf("App.GetAccessTokenResponse() = %+v", token)
+	}
+
+	if !token.Online() || token.ExpiresAt == nil {
+		t.Fatalf("App.GetAccessTokenResponse() returned an offline token %+v", token)
+	}
+	if expiresAt := start.Add(86399 * time.Second); token.ExpiresAt.Before(expiresAt) || token.ExpiresAt.After(time.Now().Add(86399*time.Second)) {
+		t.Errorf("Token.ExpiresAt = %s, expected %s", token.ExpiresAt, expiresAt)
+	}
+
+	cases := []struct {
+		now      time.Time
+		expected bool
+	}{
+		{start, false},
+		{token.ExpiresAt.Add(-2 * time.Minute), false},
+		{token.ExpiresAt.Add(-30 * time.Second), true},
+		{token.ExpiresAt.Add(time.Hour), true},
+	}
+	for _, c := range cases {
+		if actual := token.NeedsReauthorization(c.now); actual != c.expected {
+			t.Errorf("Token.NeedsReauthorization(%s) = %t, expected %t", c.now, actual, c.expected)
+		}
+	}
+}
+
+func TestAppGetAccessTokenResponseOffline(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/admin/oauth/access_token",
+		httpmock.NewStringResponder(200, `{"access_token":"footoken","scope":"read_products"}`))
+
+	app.Client = client
+	token, err := app.GetAccessTokenResponse(context.Background(), "fooshop", "foocode")
+	if err != nil {
+		t.Fatalf("App.GetAccessTokenResponse(): %v", err)
+	}
+
+	expected := &AccessTokenResponse{AccessToken: "footoken", Scope: "read_products"}
+	if !reflect.DeepEqual(token, expected) {
+		t.Errorf("App.GetAccessTokenResponse() = %+v, expected %+v", token, expected)
+	}
+	if token.Online() || token.NeedsReauthorization(time.Now().AddDate(10, 0, 0)) {
+		t.Errorf("offline token %+v should never need reauthorization", token)
+	}
+}
+
 func TestAppGetAccessTokenError(t *testing.T) {
 	setup()
 	defer teardown()
@@ -84,6 +189,7 @@ func TestAppGetAccessTokenError(t *testing.T) {
 	}
 
 	expectedError = errors.New("parse ://example.com: missing protocol scheme")
+	defer func(relPath string) { accessTokenRelPath = relPath }(accessTokenRelPath)
 	accessTokenRelPath = "://example.com" // cause NewRequest to trip a parse error
 	token, err = app.GetAccessToken(context.Background(), "fooshop", "")
 	if err == nil || !strings.Contains(err.Error(), "missing protocol scheme") {
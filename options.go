@@ -8,26 +8,91 @@ import (
 // Option is used to configure client with options
 type Option func(c *Client)
 
-// WithVersion optionally sets the api-version if the passed string is valid
+// WithVersion pins the api-version of every request, either a YYYY-MM version
+// or UnstableApiVersion. NewClient returns an error for any other version. An
+// empty version leaves the client unversioned, served by the oldest supported
+// stable version.
 func WithVersion(apiVersion string) Option {
 	return func(c *Client) {
-		pathPrefix := defaultApiPathPrefix
-		if len(apiVersion) > 0 && (apiVersionRegex.MatchString(apiVersion) || apiVersion == UnstableApiVersion) {
-			pathPrefix = fmt.Sprintf("admin/api/%s", apiVersion)
+		if apiVersion == "" {
+			apiVersion = defaultApiVersion
 		}
 		c.apiVersion = apiVersion
-		c.pathPrefix = pathPrefix
+		c.pathPrefix = defaultApiPathPrefix
+		if apiVersionRegex.MatchString(apiVersion) || apiVersion == UnstableApiVersion {
+			c.pathPrefix = fmt.Sprintf("admin/api/%s", apiVersion)
+		}
 	}
 }
 
 // WithRetry sets the number of times a request will be retried if a rate limit or service unavailable error is returned.
-// Rate limiting can be either REST API limits or GraphQL Cost limits
+// Rate limiting can be either REST API limits or GraphQL Cost limits.
+// REST requests are retried according to a DefaultRetryPolicy with retries attempts unless WithRetryPolicy is used.
 func WithRetry(retries int) Option {
 	return func(c *Client) {
 		c.retries = retries
 	}
 }
 
+// WithRetryPolicy sets the policy deciding which failed REST requests are retried and how long to back off in between.
+// Throttled GraphQL queries are still retried up to the number of times set by WithRetry.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter throttles REST requests before they are sent so that the
+// shop's rate limit is never exceeded. Share the same limiter, e.g. a
+// LeakyBucket, between every client making requests to the same shop.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// WithMiddleware wraps every request made by the client, including GraphQL queries and, when the client is set as
+// App.Client, the OAuth token exchange. Middleware is called in the order given, the first one being the outermost.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithInstrumenter reports structured events about every request attempt and GraphQL query, e.g. to record metrics or
+// traces. Use MultiInstrumenter to report to more than one instrumenter.
+func WithInstrumenter(instrumenter Instrumenter) Option {
+	return func(c *Client) {
+		c.instrumenter = instrumenter
+	}
+}
+
+// WithRedactor sets how secrets and personal data are removed from request and response bodies before they are
+// written to the debug log. By default a JSONRedactor of the DefaultRedactedKeys and DefaultRedactedPaths is used,
+// nil logs bodies unchanged.
+func WithRedactor(redactor Redactor) Option {
+	return func(c *Client) {
+		c.redactor = redactor
+	}
+}
+
+// WithStructuredLogger logs requests and responses with typed attributes, such as the shop, method, path, status and
+// Shopify's X-Request-Id, instead of as debug messages to the LeveledLoggerInterface. Other messages are still written
+// to the logger set by WithLogger.
+func WithStructuredLogger(logger StructuredLogger) Option {
+	return func(c *Client) {
+		c.structuredLog = logger
+	}
+}
+
+// WithDeprecationHandler calls handler the first time a response marks an endpoint as deprecated with the
+// X-Shopify-API-Deprecated-Reason header. Notices are also logged as warnings and returned by Client.Deprecations.
+func WithDeprecationHandler(handler func(DeprecationNotice)) Option {
+	return func(c *Client) {
+		c.onDeprecation = handler
+	}
+}
+
 func WithLogger(logger LeveledLoggerInterface) Option {
 	return func(c *Client) {
 		c.log = logger
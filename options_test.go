@@ -8,7 +8,7 @@ import (
 )
 
 func TestWithVersion(t *testing.T) {
-	c := MustNewClient(app, "fooshop", "abcd", WithVersion(testApiVersion))
+	c := MustNewClient(app, "fooshop", "abcd", WithVersion(testApiVersion), skipApiVersionCheck())
 	expected := fmt.Sprintf("admin/api/%s", testApiVersion)
 	if c.pathPrefix != expected {
 		t.Errorf("WithVersion client.pathPrefix = %s, expected %s", c.pathPrefix, expected)
@@ -32,10 +32,19 @@ func TestWithoutVersionInInitiation(t *testing.T) {
 }
 
 func TestWithVersionInvalidVersion(t *testing.T) {
-	c := MustNewClient(app, "fooshop", "abcd", WithVersion("9999-99b"))
-	expected := "admin"
-	if c.pathPrefix != expected {
-		t.Errorf("WithVersion client.pathPrefix = %s, expected %s", c.pathPrefix, expected)
+	for _, version := range []string{"9999-99b", "2024-4", "2024-13", "2024-00", "latest"} {
+		c, err := NewClient(app, "fooshop", "abcd", WithVersion(version))
+		expected := fmt.Sprintf(`invalid api version %q, expected a YYYY-MM version or "unstable"`, version)
+		if c != nil || err == nil || err.Error() != expected {
+			t.Errorf("NewClient with version %s returned %v, %v, expected error %s", version, c, err, expected)
+		}
+	}
+}
+
+func TestWithVersionStable(t *testing.T) {
+	c := MustNewClient(app, "fooshop", "abcd", WithVersion("stable"))
+	if c.pathPrefix != "admin" || c.apiVersion != defaultApiVersion {
+		t.Errorf("WithVersion client.pathPrefix = %s, client.apiVersion = %s, expected admin and stable", c.pathPrefix, c.apiVersion)
 	}
 }
 
@@ -72,3 +81,74 @@ func TestWithHTTPClient(t *testing.T) {
 		t.Errorf("WithVersion client.Client = %s, expected %s", c.Client.Timeout, expected)
 	}
 }
+
+func TestWithRateLimiter(t *testing.T) {
+	limiter := NewLeakyBucket(40, 2)
+	c := MustNewClient(app, "fooshop", "abcd", WithRateLimiter(limiter))
+
+	if c.rateLimiter != limiter {
+		t.Errorf("WithRateLimiter client.rateLimiter = %v, expected %v", c.rateLimiter, limiter)
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	policy := &DefaultRetryPolicy{MaxAttempts: 5}
+	c := MustNewClient(app, "fooshop", "abcd", WithRetryPolicy(policy))
+
+	if c.retryPolicy != policy {
+		t.Errorf("WithRetryPolicy client.retryPolicy = %v, expected %v", c.retryPolicy, policy)
+	}
+}
+
+func TestWithMiddleware(t *testing.T) {
+	noop := func(next RequestHandler) RequestHandler { return next }
+	c := MustNewClient(app, "fooshop", "abcd", WithMiddleware(noop), WithMiddleware(noop, noop))
+
+	if len(c.middleware) != 3 {
+		t.Errorf("WithMiddleware len(client.middleware) = %d, expected 3", len(c.middleware))
+	}
+}
+
+func TestWithInstrumenter(t *testing.T) {
+	instrumenter := &MetricsInstrumenter{}
+	c := MustNewClient(app, "fooshop", "abcd", WithInstrumenter(instrumenter))
+
+	if c.instrumenter != instrumenter {
+		t.Errorf("WithInstrumenter client.instrumenter = %v, expected %v", c.instrumenter, instrumenter)
+	}
+}
+
+func TestWithRedactor(t *testing.T) {
+	c := MustNewClient(app, "fooshop", "abcd")
+	if _, ok := c.redactor.(*JSONRedactor); !ok {
+		t.Errorf("client.redactor = %#v, expected a JSONRedactor by default", c.redactor)
+	}
+
+	redactor := &JSONRedactor{Keys: []string{"note"}}
+	c = MustNewClient(app, "fooshop", "abcd", WithRedactor(redactor))
+	if c.redactor != redactor {
+		t.Errorf("WithRedactor client.redactor = %v, expected %v", c.redactor, redactor)
+	}
+}
+
+func TestWithStructuredLogger(t *testing.T) {
+	logger := NewLeveledStructuredLogger(&LeveledLogger{})
+	c := MustNewClient(app, "fooshop", "abcd", WithStructuredLogger(logger))
+
+	if c.structuredLog != logger {
+		t.Errorf("WithStructuredLogger client.structuredLog = %v, expected %v", c.structuredLog, logger)
+	}
+}
+
+func TestWithDeprecationHandler(t *testing.T) {
+	called := false
+	c := MustNewClient(app, "fooshop", "abcd", WithDeprecationHandler(func(DeprecationNotice) { called = true }))
+
+	if c.onDeprecation == nil {
+		t.Fatal("WithDeprecationHandler client.onDeprecation is nil")
+	}
+	c.onDeprecation(DeprecationNotice{})
+	if !called {
+		t.Error("WithDeprecationHandler handler was not set")
+	}
+}
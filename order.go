@@ -20,6 +20,8 @@ const (
 type OrderService interface {
 	List(context.Context, interface{}) ([]Order, error)
 	ListWithPagination(context.Context, interface{}) ([]Order, *Pagination, error)
+	Iterate(interface{}) *Iterator[Order]
+	StreamWithPagination(context.Context, interface{}, func(Order) error) (*Pagination, error)
 	Count(context.Context, interface{}) (int, error)
 	Get(context.Context, uint64, interface{}) (*Order, error)
 	Create(context.Context, Order) (*Order, error)
@@ -549,6 +551,26 @@ func (s *OrderServiceOp) ListWithPagination(ctx context.Context, options interfa
 	return resource.Orders, pagination, nil
 }
 
+// Iterate returns an Iterator over every order, requesting pages as needed.
+func (s *OrderServiceOp) Iterate(options interface{}) *Iterator[Order] {
+	return NewIterator(s.ListWithPagination, options)
+}
+
+// StreamWithPagination calls fn with every order of a page while the response
+// is read, instead of decoding the whole page at once, and returns pagination
+// to retrieve next/previous results. An error returned by fn stops the stream
+// and is returned.
+func (s *OrderServiceOp) StreamWithPagination(ctx context.Context, options interface{}, fn func(Order) error) (*Pagination, error) {
+	path := fmt.Sprintf("%s.json", ordersBasePath)
+	return s.client.StreamWithPagination(ctx, path, ordersResourceName, options, func(decoder *json.Decoder) error {
+		var order Order
+		if err := decoder.Decode(&order); err != nil {
+			return err
+		}
+		return fn(order)
+	})
+}
+
 // Count orders
 func (s *OrderServiceOp) Count(ctx context.Context, options interface{}) (int, error) {
 	path := fmt.Sprintf("%s/count.json", ordersBasePath)
@@ -16,6 +16,7 @@ const (
 type OrderRiskService interface {
 	List(context.Context, uint64, interface{}) ([]OrderRisk, error)
 	ListWithPagination(context.Context, uint64, interface{}) ([]OrderRisk, *Pagination, error)
+	Iterate(uint64, interface{}) *Iterator[OrderRisk]
 	Get(context.Context, uint64, uint64, interface{}) (*OrderRisk, error)
 	Create(context.Context, uint64, OrderRisk) (*OrderRisk, error)
 	Update(context.Context, uint64, uint64, OrderRisk) (*OrderRisk, error)
@@ -91,6 +92,13 @@ func (s *OrderRiskServiceOp) ListWithPagination(ctx context.Context, orderId uin
 	return resource.OrderRisk, pagination, nil
 }
 
+// Iterate returns an Iterator over every risk of an order, requesting pages as needed.
+func (s *OrderRiskServiceOp) Iterate(orderId uint64, options interface{}) *Iterator[OrderRisk] {
+	return NewIterator(func(ctx context.Context, options interface{}) ([]OrderRisk, *Pagination, error) {
+		return s.ListWithPagination(ctx, orderId, options)
+	}, options)
+}
+
 // Get individual order
 func (s *OrderRiskServiceOp) Get(ctx context.Context, orderId uint64, riskId uint64, options interface{}) (*OrderRisk, error) {
 	path := fmt.Sprintf("%s/%d/%s/%d.json", ordersRiskBasePath, orderId, ordersRiskResourceName, riskId)
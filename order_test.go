@@ -34,6 +34,45 @@ func TestOrderListError(t *testing.T) {
 	}
 }
 
+func TestOrderStreamWithPagination(t *testing.T) {
+	setup()
+	defer teardown()
+
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/orders.json", client.pathPrefix)
+	nextURL := fmt.Sprintf("<%s?page_info=pg2&limit=2>; rel=\"next\"", listURL)
+
+	httpmock.RegisterResponder("GET", listURL,
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, `{"orders": [{"id":1},{"id":2}]}`)
+			resp.Header.Set("Link", nextURL)
+			return resp, nil
+		})
+
+	var ids []uint64
+	pagination, err := client.Order.StreamWithPagination(context.Background(), nil, func(item Order) error {
+		ids = append(ids, item.Id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Order.StreamWithPagination returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(ids, []uint64{1, 2}) {
+		t.Errorf("Order.StreamWithPagination streamed %v, expected [1 2]", ids)
+	}
+
+	expectedPagination := &Pagination{NextPageOptions: &ListOptions{PageInfo: "pg2", Limit: 2}}
+	if !reflect.DeepEqual(pagination, expectedPagination) {
+		t.Errorf("Order.StreamWithPagination returned pagination %#v, expected %#v", pagination, expectedPagination)
+	}
+
+	// errors of the callback stop the stream
+	stop := errors.New("stop")
+	if _, err := client.Order.StreamWithPagination(context.Background(), nil, func(Order) error { return stop }); err != stop {
+		t.Errorf("Order.StreamWithPagination returned %v, expected %v", err, stop)
+	}
+}
+
 func TestOrderListWithPagination(t *testing.T) {
 	setup()
 	defer teardown()
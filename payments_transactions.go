@@ -13,6 +13,7 @@ const paymentsTransactionsBasePath = "shopify_payments/balance/transactions"
 type PaymentsTransactionsService interface {
 	List(context.Context, interface{}) ([]PaymentsTransactions, error)
 	ListWithPagination(context.Context, interface{}) ([]PaymentsTransactions, *Pagination, error)
+	Iterate(interface{}) *Iterator[PaymentsTransactions]
 	Get(context.Context, uint64, interface{}) (*PaymentsTransactions, error)
 }
 
@@ -100,6 +101,11 @@ func (s *PaymentsTransactionsServiceOp) ListWithPagination(ctx context.Context,
 	return resource.PaymentsTransactions, pagination, nil
 }
 
+// Iterate returns an Iterator over every payments transaction, requesting pages as needed.
+func (s *PaymentsTransactionsServiceOp) Iterate(options interface{}) *Iterator[PaymentsTransactions] {
+	return NewIterator(s.ListWithPagination, options)
+}
+
 // Get individual PaymentsTransactions
 func (s *PaymentsTransactionsServiceOp) Get(ctx context.Context, payoutId uint64, options interface{}) (*PaymentsTransactions, error) {
 	path := fmt.Sprintf("%s/%d.json", paymentsTransactionsBasePath, payoutId)
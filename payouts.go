@@ -15,6 +15,7 @@ const payoutsBasePath = "shopify_payments/payouts"
 type PayoutsService interface {
 	List(context.Context, interface{}) ([]Payout, error)
 	ListWithPagination(context.Context, interface{}) ([]Payout, *Pagination, error)
+	Iterate(interface{}) *Iterator[Payout]
 	Get(context.Context, uint64, interface{}) (*Payout, error)
 }
 
@@ -87,6 +88,11 @@ func (s *PayoutsServiceOp) ListWithPagination(ctx context.Context, options inter
 	return resource.Payouts, pagination, nil
 }
 
+// Iterate returns an Iterator over every payout, requesting pages as needed.
+func (s *PayoutsServiceOp) Iterate(options interface{}) *Iterator[Payout] {
+	return NewIterator(s.ListWithPagination, options)
+}
+
 // Get individual payout
 func (s *PayoutsServiceOp) Get(ctx context.Context, id uint64, options interface{}) (*Payout, error) {
 	path := fmt.Sprintf("%s/%d.json", payoutsBasePath, id)
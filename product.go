@@ -2,6 +2,7 @@ package goshopify
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"time"
@@ -21,6 +22,8 @@ var linkRegex = regexp.MustCompile(`^ *<([^>]+)>; rel="(previous|next)" *$`)
 type ProductService interface {
 	List(context.Context, interface{}) ([]Product, error)
 	ListWithPagination(context.Context, interface{}) ([]Product, *Pagination, error)
+	Iterate(interface{}) *Iterator[Product]
+	StreamWithPagination(context.Context, interface{}, func(Product) error) (*Pagination, error)
 	Count(context.Context, interface{}) (int, error)
 	Get(context.Context, uint64, interface{}) (*Product, error)
 	Create(context.Context, Product) (*Product, error)
@@ -142,6 +145,26 @@ func (s *ProductServiceOp) ListWithPagination(ctx context.Context, options inter
 	return resource.Products, pagination, nil
 }
 
+// Iterate returns an Iterator over every product, requesting pages as needed.
+func (s *ProductServiceOp) Iterate(options interface{}) *Iterator[Product] {
+	return NewIterator(s.ListWithPagination, options)
+}
+
+// StreamWithPagination calls fn with every product of a page while the response
+// is read, instead of decoding the whole page at once, and returns pagination
+// to retrieve next/previous results. An error returned by fn stops the stream
+// and is returned.
+func (s *ProductServiceOp) StreamWithPagination(ctx context.Context, options interface{}, fn func(Product) error) (*Pagination, error) {
+	path := fmt.Sprintf("%s.json", productsBasePath)
+	return s.client.StreamWithPagination(ctx, path, productsResourceName, options, func(decoder *json.Decoder) error {
+		var product Product
+		if err := decoder.Decode(&product); err != nil {
+			return err
+		}
+		return fn(product)
+	})
+}
+
 // Count products
 func (s *ProductServiceOp) Count(ctx context.Context, options interface{}) (int, error) {
 	path := fmt.Sprintf("%s/count.json", productsBasePath)
@@ -14,6 +14,7 @@ const productListingBasePath = "product_listings"
 type ProductListingService interface {
 	List(context.Context, interface{}) ([]ProductListing, error)
 	ListWithPagination(context.Context, interface{}) ([]ProductListing, *Pagination, error)
+	Iterate(interface{}) *Iterator[ProductListing]
 	Count(context.Context, interface{}) (int, error)
 	Get(context.Context, uint64, interface{}) (*ProductListing, error)
 	GetProductIds(context.Context, interface{}) ([]uint64, error)
@@ -96,6 +97,11 @@ func (s *ProductListingServiceOp) ListWithPagination(ctx context.Context, option
 	return resource.ProductListings, pagination, nil
 }
 
+// Iterate returns an Iterator over every product listing, requesting pages as needed.
+func (s *ProductListingServiceOp) Iterate(options interface{}) *Iterator[ProductListing] {
+	return NewIterator(s.ListWithPagination, options)
+}
+
 // Count products listings published to your sales channel app
 func (s *ProductListingServiceOp) Count(ctx context.Context, options interface{}) (int, error) {
 	path := fmt.Sprintf("%s/count.json", productListingBasePath)
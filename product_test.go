@@ -82,6 +82,45 @@ func TestProductListError(t *testing.T) {
 	}
 }
 
+func TestProductStreamWithPagination(t *testing.T) {
+	setup()
+	defer teardown()
+
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/products.json", client.pathPrefix)
+	nextURL := fmt.Sprintf("<%s?page_info=pg2&limit=2>; rel=\"next\"", listURL)
+
+	httpmock.RegisterResponder("GET", listURL,
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, `{"products": [{"id":1},{"id":2}]}`)
+			resp.Header.Set("Link", nextURL)
+			return resp, nil
+		})
+
+	var ids []uint64
+	pagination, err := client.Product.StreamWithPagination(context.Background(), nil, func(item Product) error {
+		ids = append(ids, item.Id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Product.StreamWithPagination returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(ids, []uint64{1, 2}) {
+		t.Errorf("Product.StreamWithPagination streamed %v, expected [1 2]", ids)
+	}
+
+	expectedPagination := &Pagination{NextPageOptions: &ListOptions{PageInfo: "pg2", Limit: 2}}
+	if !reflect.DeepEqual(pagination, expectedPagination) {
+		t.Errorf("Product.StreamWithPagination returned pagination %#v, expected %#v", pagination, expectedPagination)
+	}
+
+	// errors of the callback stop the stream
+	stop := errors.New("stop")
+	if _, err := client.Product.StreamWithPagination(context.Background(), nil, func(Product) error { return stop }); err != stop {
+		t.Errorf("Product.StreamWithPagination returned %v, expected %v", err, stop)
+	}
+}
+
 func TestProductListWithPagination(t *testing.T) {
 	setup()
 	defer teardown()
@@ -0,0 +1,147 @@
+package goshopify
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Default REST bucket of a standard Shopify plan, see
+	// https://shopify.dev/docs/api/usage/rate-limits#rest-admin-api-rate-limits
+	defaultBucketSize = 40
+	defaultLeakRate   = 2
+)
+
+// RateLimiter is used to throttle REST requests before they are sent, see
+// WithRateLimiter.
+type RateLimiter interface {
+	// Wait blocks until a request may be sent or the context is done.
+	Wait(ctx context.Context) error
+
+	// Observe is called with the request count and bucket size reported by
+	// Shopify in the X-Shopify-Shop-Api-Call-Limit header of each response.
+	Observe(requestCount, bucketSize int)
+}
+
+// LeakyBucket is a RateLimiter modelling Shopify's leaky bucket algorithm.
+// Every request adds one to the bucket which leaks at a constant rate, callers
+// are blocked while the bucket is full.
+//
+// A LeakyBucket is safe for concurrent use and should be shared between all
+// clients making requests to the same shop.
+type LeakyBucket struct {
+	mu sync.Mutex
+
+	// capacity of the bucket and the number of requests leaked per second
+	size     float64
+	leakRate float64
+
+	// number of requests in the bucket as of lastLeak
+	level    float64
+	lastLeak time.Time
+
+	// Internal testing use only.
+	now func() time.Time
+}
+
+// NewLeakyBucket returns a LeakyBucket of the given size which leaks
+// leakRate requests per second. Zero values use the limits of a standard
+// Shopify plan, 40 requests leaking at 2 per second.
+func NewLeakyBucket(size int, leakRate float64) *LeakyBucket {
+	if size <= 0 {
+		size = defaultBucketSize
+	}
+	if leakRate <= 0 {
+		leakRate = defaultLeakRate
+	}
+
+	return &LeakyBucket{
+		size:     float64(size),
+		leakRate: leakRate,
+		now:      time.Now,
+	}
+}
+
+// Wait blocks until there is room in the bucket for another request, or until
+// ctx is done in which case the context's error is returned.
+func (b *LeakyBucket) Wait(ctx context.Context) error {
+	for {
+		wait := b.reserve()
+		if wait <= 0 {
+			return nil
+		}
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve adds a request to the bucket if there is room, otherwise it returns
+// how long to wait until there will be.
+func (b *LeakyBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.leak()
+	if b.level+1 <= b.size {
+		b.level++
+		return 0
+	}
+
+	overflow := b.level + 1 - b.size
+	return time.Duration(overflow / b.leakRate * float64(time.Second))
+}
+
+// leak drains the bucket for the time passed since the last leak. Callers
+// must hold b.mu.
+func (b *LeakyBucket) leak() {
+	now := b.now()
+	if !b.lastLeak.IsZero() {
+		b.level -= now.Sub(b.lastLeak).Seconds() * b.leakRate
+		if b.level < 0 {
+			b.level = 0
+		}
+	}
+	b.lastLeak = now
+}
+
+// Observe synchronises the bucket with the state reported by Shopify. The
+// bucket never drains below the reported request count so that requests
+// made by other processes against the same shop are accounted for, and the
+// leak rate is scaled along with the bucket size when the shop's plan has a
+// larger bucket.
+func (b *LeakyBucket) Observe(requestCount, bucketSize int) {
+	if bucketSize <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.leak()
+	if size := float64(bucketSize); size != b.size {
+		b.leakRate = b.leakRate * size / b.size
+		b.size = size
+	}
+	if count := float64(requestCount); count > b.level {
+		b.level = count
+	}
+}
+
+// parseCallLimit parses the X-Shopify-Shop-Api-Call-Limit header, which has
+// the form "requestCount/bucketSize".
+func parseCallLimit(header http.Header) (requestCount, bucketSize int, ok bool) {
+	s := strings.Split(header.Get("X-Shopify-Shop-Api-Call-Limit"), "/")
+	if len(s) != 2 {
+		return 0, 0, false
+	}
+
+	requestCount, _ = strconv.Atoi(s[0])
+	bucketSize, _ = strconv.Atoi(s[1])
+	return requestCount, bucketSize, true
+}
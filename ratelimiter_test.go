@@ -0,0 +1,170 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// fakeClock is a controllable time source for the LeakyBucket
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func TestNewLeakyBucketDefaults(t *testing.T) {
+	b := NewLeakyBucket(0, 0)
+	if b.size != defaultBucketSize || b.leakRate != defaultLeakRate {
+		t.Errorf("NewLeakyBucket(0, 0) = %v/%v, expected %v/%v", b.size, b.leakRate, defaultBucketSize, defaultLeakRate)
+	}
+}
+
+func TestLeakyBucketReserve(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewLeakyBucket(2, 2)
+	b.now = clock.Now
+
+	for i := 0; i < 2; i++ {
+		if wait := b.reserve(); wait != 0 {
+			t.Fatalf("reserve() %d waited %s, expected no wait", i, wait)
+		}
+	}
+
+	if wait := b.reserve(); wait != 500*time.Millisecond {
+		t.Errorf("reserve() on full bucket waited %s, expected 500ms", wait)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if wait := b.reserve(); wait != 0 {
+		t.Errorf("reserve() after leak waited %s, expected no wait", wait)
+	}
+}
+
+func TestLeakyBucketObserve(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewLeakyBucket(40, 2)
+	b.now = clock.Now
+
+	// requests made elsewhere fill the bucket
+	b.Observe(40, 40)
+	if wait := b.reserve(); wait != 500*time.Millisecond {
+		t.Errorf("reserve() after Observe(40, 40) waited %s, expected 500ms", wait)
+	}
+
+	// a lower count never drains the local bucket
+	b.Observe(10, 40)
+	if b.level != 40 {
+		t.Errorf("Observe(10, 40) level = %v, expected 40", b.level)
+	}
+
+	// larger plans leak faster
+	b.Observe(0, 80)
+	if b.size != 80 || b.leakRate != 4 {
+		t.Errorf("Observe(0, 80) = %v/%v, expected 80/4", b.size, b.leakRate)
+	}
+
+	// invalid headers are ignored
+	b.Observe(0, 0)
+	if b.size != 80 {
+		t.Errorf("Observe(0, 0) size = %v, expected 80", b.size)
+	}
+}
+
+func TestLeakyBucketWaitContextCancelled(t *testing.T) {
+	b := NewLeakyBucket(1, 0.001)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() returned error %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() returned %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestDoWithRateLimiter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	bucket := NewLeakyBucket(2, 20)
+	WithRateLimiter(bucket)(client)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/products/count.json", client.pathPrefix),
+		createResponderWithHeaders(200, `{"count": 3}`, map[string]string{
+			"X-Shopify-Shop-Api-Call-Limit": "1/2",
+		}))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{}}`))
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Product.Count(context.Background(), nil); err != nil {
+				t.Errorf("Product.Count returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 2 requests fit in the bucket, the other 2 wait for it to leak at 20/s
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("rate limited requests took %s, expected at least 50ms", elapsed)
+	}
+
+	// GraphQL requests are not counted against the REST bucket
+	bucket.mu.Lock()
+	level := bucket.level
+	bucket.mu.Unlock()
+	if err := client.GraphQL.Query(context.Background(), "query {}", nil, nil); err != nil {
+		t.Errorf("GraphQL.Query returned error: %v", err)
+	}
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+	if bucket.level > level {
+		t.Errorf("GraphQL.Query increased the bucket level from %v to %v", level, bucket.level)
+	}
+}
+
+func TestParseCallLimit(t *testing.T) {
+	cases := []struct {
+		header string
+		count  int
+		size   int
+		ok     bool
+	}{
+		{"15/40", 15, 40, true},
+		{"invalid/invalid", 0, 0, true},
+		{"", 0, 0, false},
+	}
+
+	for _, c := range cases {
+		h := http.Header{}
+		h.Set("X-Shopify-Shop-Api-Call-Limit", c.header)
+		count, size, ok := parseCallLimit(h)
+		if count != c.count || size != c.size || ok != c.ok {
+			t.Errorf("parseCallLimit(%q) = %d, %d, %v expected %d, %d, %v", c.header, count, size, ok, c.count, c.size, c.ok)
+		}
+	}
+}
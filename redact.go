@@ -0,0 +1,171 @@
+package goshopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// RedactedValue replaces redacted values in logged bodies.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedKeys are the JSON object keys redacted from logged bodies by
+// default: credentials exchanged during OAuth, card details from
+// PaymentDetails and customer contact details.
+var DefaultRedactedKeys = []string{
+	// credentials
+	"access_token",
+	"client_secret",
+	"password",
+	"password_confirmation",
+
+	// payment details
+	"credit_card_bin",
+	"credit_card_number",
+
+	// customer contact details
+	"email",
+	"contact_email",
+	"phone",
+	"first_name",
+	"last_name",
+	"address1",
+	"address2",
+	"zip",
+	"latitude",
+	"longitude",
+	"browser_ip",
+}
+
+// DefaultRedactedPaths are the JSON paths redacted from logged bodies by
+// default, for keys such as "code" which are only secret in some bodies: the
+// authorization code of the OAuth token exchange and gift card codes.
+var DefaultRedactedPaths = []string{
+	"$.code",
+	"$.gift_card.code",
+	"$.gift_cards[*].code",
+}
+
+// Redactor removes secrets and personal data from request and response bodies
+// before they are written to the debug log, see WithRedactor.
+type Redactor interface {
+	Redact(body []byte) []byte
+}
+
+// JSONRedactor replaces the values of matching keys and paths in JSON bodies
+// with RedactedValue. Bodies which are not JSON are logged unchanged.
+//
+// The client redacts DefaultRedactedKeys and DefaultRedactedPaths unless
+// configured otherwise, e.g. to also redact order notes:
+//
+//	redactor := &goshopify.JSONRedactor{
+//		Keys:  append(goshopify.DefaultRedactedKeys, "note"),
+//		Paths: append(goshopify.DefaultRedactedPaths, "$.order.note_attributes[*].value"),
+//	}
+//	client, err := goshopify.NewClient(app, "shopname", "token", goshopify.WithRedactor(redactor))
+type JSONRedactor struct {
+	// Keys are object keys redacted wherever they appear.
+	Keys []string
+
+	// Paths are redacted from the root of the body. A path is a list of keys
+	// separated by dots, optionally starting with "$.", where array elements
+	// are selected with "[n]" and "*" or "[*]" matches any key or element.
+	Paths []string
+}
+
+// Redact implements Redactor.
+func (r *JSONRedactor) Redact(body []byte) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return body
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return body
+	}
+
+	keys := make(map[string]bool, len(r.Keys))
+	for _, k := range r.Keys {
+		keys[k] = true
+	}
+
+	paths := make([][]string, 0, len(r.Paths))
+	for _, p := range r.Paths {
+		paths = append(paths, parseRedactPath(p))
+	}
+
+	v = redactValue(v, nil, keys, paths)
+
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactValue walks a decoded JSON value, replacing the values of matching
+// keys and paths.
+func redactValue(v interface{}, path []string, keys map[string]bool, paths [][]string) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, child := range value {
+			childPath := append(path[:len(path):len(path)], k)
+			if keys[k] || matchRedactPath(childPath, paths) {
+				value[k] = RedactedValue
+				continue
+			}
+			value[k] = redactValue(child, childPath, keys, paths)
+		}
+	case []interface{}:
+		for i, child := range value {
+			childPath := append(path[:len(path):len(path)], strconv.Itoa(i))
+			if matchRedactPath(childPath, paths) {
+				value[i] = RedactedValue
+				continue
+			}
+			value[i] = redactValue(child, childPath, keys, paths)
+		}
+	}
+	return v
+}
+
+// parseRedactPath splits a path like "$.orders[*].email" into its segments.
+func parseRedactPath(p string) []string {
+	p = strings.TrimPrefix(p, "$")
+	p = strings.ReplaceAll(p, "[", ".")
+	p = strings.ReplaceAll(p, "]", "")
+
+	var segments []string
+	for _, s := range strings.Split(p, ".") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// matchRedactPath reports whether the path matches any of the redacted paths.
+func matchRedactPath(path []string, paths [][]string) bool {
+	for _, p := range paths {
+		if len(p) != len(path) {
+			continue
+		}
+
+		match := true
+		for i := range p {
+			if p[i] != "*" && p[i] != path[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package goshopify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestJSONRedactorRedact(t *testing.T) {
+	cases := []struct {
+		description string
+		redactor    *JSONRedactor
+		body        string
+		expected    string
+	}{
+		{
+			"default keys are redacted at any depth",
+			&JSONRedactor{Keys: DefaultRedactedKeys, Paths: DefaultRedactedPaths},
+			`{"client_id":"key","client_secret":"hush","code":"abc"}`,
+			`{"client_id":"key","client_secret":"[REDACTED]","code":"[REDACTED]"}`,
+		},
+		{
+			"codes are only redacted under the default paths",
+			&JSONRedactor{Keys: DefaultRedactedKeys, Paths: DefaultRedactedPaths},
+			`{"gift_card":{"code":"gc1"},"gift_cards":[{"code":"gc2"}],"discount_code":{"code":"SUMMER"},"shipping_lines":[{"code":"Standard"}]}`,
+			`{"discount_code":{"code":"SUMMER"},"gift_card":{"code":"[REDACTED]"},"gift_cards":[{"code":"[REDACTED]"}],"shipping_lines":[{"code":"Standard"}]}`,
+		},
+		{
+			"nested customer and payment details",
+			&JSONRedactor{Keys: DefaultRedactedKeys},
+			`{"order":{"id":1,"total_price":"10.00","customer":{"email":"a@b.c","addresses":[{"zip":"123","country_code":"CA"}]},"payment_details":{"credit_card_bin":"411111","credit_card_company":"Visa"}}}`,
+			`{"order":{"customer":{"addresses":[{"country_code":"CA","zip":"[REDACTED]"}],"email":"[REDACTED]"},"id":1,"payment_details":{"credit_card_bin":"[REDACTED]","credit_card_company":"Visa"},"total_price":"10.00"}}`,
+		},
+		{
+			"paths with wildcards and indexes",
+			&JSONRedactor{Paths: []string{"$.orders[*].note", "orders.0.tags", "$.count"}},
+			`{"count":2,"orders":[{"note":"a","tags":"x"},{"note":"b","tags":"y"}]}`,
+			`{"count":"[REDACTED]","orders":[{"note":"[REDACTED]","tags":"[REDACTED]"},{"note":"[REDACTED]","tags":"y"}]}`,
+		},
+		{
+			"large numbers are kept exact",
+			&JSONRedactor{Keys: DefaultRedactedKeys},
+			`{"id":9007199254740993,"email":"a@b.c"}`,
+			`{"email":"[REDACTED]","id":9007199254740993}`,
+		},
+		{
+			"non json bodies are unchanged",
+			&JSONRedactor{Keys: DefaultRedactedKeys},
+			`<html>code</html>`,
+			`<html>code</html>`,
+		},
+		{
+			"invalid json bodies are unchanged",
+			&JSONRedactor{Keys: DefaultRedactedKeys},
+			`{"email":`,
+			`{"email":`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			actual := string(c.redactor.Redact([]byte(c.body)))
+			if actual != c.expected {
+				t.Errorf("Redact(%s) = %s, expected %s", c.body, actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestDoRedactsDebugLog(t *testing.T) {
+	setup()
+	defer teardown()
+
+	out := &bytes.Buffer{}
+	WithLogger(&LeveledLogger{Level: LevelDebug, stdoutOverride: out, stderrOverride: out})(client)
+	app.Client = client
+
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/admin/oauth/access_token",
+		httpmock.NewStringResponder(200, `{"access_token":"shpat_secret","scope":"read_products"}`))
+
+	token, err := app.GetAccessToken(context.Background(), "fooshop", "authcode")
+	if err != nil {
+		t.Fatalf("App.GetAccessToken returned error: %v", err)
+	}
+	if token != "shpat_secret" {
+		t.Errorf("App.GetAccessToken returned %s, the response must not be redacted", token)
+	}
+
+	logged := out.String()
+	for _, secret := range []string{"hush", "authcode", "shpat_secret"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("debug log contains %q: %s", secret, logged)
+		}
+	}
+	if !strings.Contains(logged, fmt.Sprintf(`"client_id":"%s"`, app.ApiKey)) {
+		t.Errorf("debug log is missing the request body: %s", logged)
+	}
+}
+
+func TestDoWithoutRedactor(t *testing.T) {
+	setup()
+	defer teardown()
+
+	out := &bytes.Buffer{}
+	WithLogger(&LeveledLogger{Level: LevelDebug, stdoutOverride: out, stderrOverride: out})(client)
+	WithRedactor(nil)(client)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/customers/1.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"customer":{"id":1,"email":"a@b.c"}}`))
+
+	if _, err := client.Customer.Get(context.Background(), 1, nil); err != nil {
+		t.Fatalf("Customer.Get returned error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "a@b.c") {
+		t.Errorf("debug log should not be redacted: %s", out.String())
+	}
+}
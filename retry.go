@@ -0,0 +1,132 @@
+package goshopify
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 250 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// RetryPolicy decides whether a failed request is retried and how long to
+// wait before doing so, see WithRetryPolicy.
+type RetryPolicy interface {
+	// ShouldRetry is called after every failed attempt. resp is nil when no
+	// response was received, e.g. on network errors and timeouts, and err is
+	// either the transport error or the error decoded from the response.
+	// attempts is the number of attempts made so far and elapsed the time
+	// since the first attempt was sent.
+	ShouldRetry(req *http.Request, resp *http.Response, err error, attempts int, elapsed time.Duration) (bool, time.Duration)
+}
+
+// DefaultRetryPolicy retries rate limited requests after the Retry-After
+// period given by Shopify, and server errors, timeouts and network errors
+// with exponential backoff and full jitter.
+//
+// Rate limited (429) and service unavailable (503) requests are never
+// processed by Shopify so they are always retried. Other server and network
+// errors may happen after a request has been processed, so POST and PATCH
+// requests are only retried for those when RetryNonIdempotent is set.
+type DefaultRetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the upper bound of the first backoff, doubling on every
+	// further attempt up to MaxDelay. Defaults to 250ms and 30s.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// MaxElapsed caps the total time spent on a request, a retry is not
+	// attempted if its wait would exceed it. Zero means no cap.
+	MaxElapsed time.Duration
+
+	// RetryNonIdempotent allows POST and PATCH requests to be retried on
+	// server and network errors.
+	RetryNonIdempotent bool
+}
+
+// ShouldRetry implements RetryPolicy.
+func (p *DefaultRetryPolicy) ShouldRetry(req *http.Request, resp *http.Response, err error, attempts int, elapsed time.Duration) (bool, time.Duration) {
+	if attempts >= p.MaxAttempts {
+		return false, 0
+	}
+
+	// the caller gave up, don't bother
+	if req.Context().Err() != nil {
+		return false, 0
+	}
+
+	var retry bool
+	var wait time.Duration
+
+	if resp == nil {
+		// network errors and timeouts
+		retry = p.RetryNonIdempotent || isIdempotent(req.Method)
+	} else {
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests:
+			retry = true
+			wait = retryAfter(resp.Header)
+		case http.StatusServiceUnavailable:
+			retry = true
+		case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+			retry = p.RetryNonIdempotent || isIdempotent(req.Method)
+		}
+	}
+
+	if !retry {
+		return false, 0
+	}
+
+	if wait <= 0 {
+		wait = p.backoff(attempts)
+	}
+
+	if p.MaxElapsed > 0 && elapsed+wait > p.MaxElapsed {
+		return false, 0
+	}
+
+	return true, wait
+}
+
+// backoff returns a random wait between zero and the exponential backoff of
+// the given attempt.
+func (p *DefaultRetryPolicy) backoff(attempts int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	max := p.MaxDelay
+	if max <= 0 {
+		max = defaultRetryMaxDelay
+	}
+
+	ceiling := float64(base) * math.Pow(2, float64(attempts-1))
+	if ceiling > float64(max) {
+		ceiling = float64(max)
+	}
+
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// isIdempotent reports whether sending a request with the method more than
+// once has the same effect as sending it once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header, which Shopify sends in seconds.
+func retryAfter(header http.Header) time.Duration {
+	f, _ := strconv.ParseFloat(header.Get("Retry-After"), 64)
+	return time.Duration(f * float64(time.Second))
+}
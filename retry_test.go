@@ -0,0 +1,233 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestDefaultRetryPolicyShouldRetry(t *testing.T) {
+	get, _ := http.NewRequest("GET", "https://fooshop.myshopify.com/foo", nil)
+	post, _ := http.NewRequest("POST", "https://fooshop.myshopify.com/foo", nil)
+
+	response := func(status int, headers map[string]string) *http.Response {
+		resp := httpmock.NewStringResponse(status, "")
+		for k, v := range headers {
+			resp.Header.Set(k, v)
+		}
+		return resp
+	}
+
+	cases := []struct {
+		description string
+		policy      DefaultRetryPolicy
+		req         *http.Request
+		resp        *http.Response
+		attempts    int
+		elapsed     time.Duration
+		retry       bool
+		wait        time.Duration
+	}{
+		{
+			description: "rate limited waits for Retry-After",
+			policy:      DefaultRetryPolicy{MaxAttempts: 3},
+			req:         post,
+			resp:        response(http.StatusTooManyRequests, map[string]string{"Retry-After": "1.5"}),
+			attempts:    1,
+			retry:       true,
+			wait:        1500 * time.Millisecond,
+		},
+		{
+			description: "out of attempts",
+			policy:      DefaultRetryPolicy{MaxAttempts: 3},
+			req:         get,
+			resp:        response(http.StatusTooManyRequests, map[string]string{"Retry-After": "1.5"}),
+			attempts:    3,
+			retry:       false,
+		},
+		{
+			description: "retries disabled",
+			policy:      DefaultRetryPolicy{},
+			req:         get,
+			resp:        response(http.StatusServiceUnavailable, nil),
+			attempts:    1,
+			retry:       false,
+		},
+		{
+			description: "service unavailable post",
+			policy:      DefaultRetryPolicy{MaxAttempts: 3},
+			req:         post,
+			resp:        response(http.StatusServiceUnavailable, nil),
+			attempts:    1,
+			retry:       true,
+		},
+		{
+			description: "bad gateway get",
+			policy:      DefaultRetryPolicy{MaxAttempts: 3},
+			req:         get,
+			resp:        response(http.StatusBadGateway, nil),
+			attempts:    1,
+			retry:       true,
+		},
+		{
+			description: "bad gateway post",
+			policy:      DefaultRetryPolicy{MaxAttempts: 3},
+			req:         post,
+			resp:        response(http.StatusBadGateway, nil),
+			attempts:    1,
+			retry:       false,
+		},
+		{
+			description: "bad gateway post allowed",
+			policy:      DefaultRetryPolicy{MaxAttempts: 3, RetryNonIdempotent: true},
+			req:         post,
+			resp:        response(http.StatusBadGateway, nil),
+			attempts:    1,
+			retry:       true,
+		},
+		{
+			description: "network error get",
+			policy:      DefaultRetryPolicy{MaxAttempts: 3},
+			req:         get,
+			attempts:    1,
+			retry:       true,
+		},
+		{
+			description: "network error post",
+			policy:      DefaultRetryPolicy{MaxAttempts: 3},
+			req:         post,
+			attempts:    1,
+			retry:       false,
+		},
+		{
+			description: "client errors are not retried",
+			policy:      DefaultRetryPolicy{MaxAttempts: 3},
+			req:         get,
+			resp:        response(http.StatusUnprocessableEntity, nil),
+			attempts:    1,
+			retry:       false,
+		},
+		{
+			description: "total time exceeded",
+			policy:      DefaultRetryPolicy{MaxAttempts: 3, MaxElapsed: 2 * time.Second},
+			req:         get,
+			resp:        response(http.StatusTooManyRequests, map[string]string{"Retry-After": "1.5"}),
+			attempts:    1,
+			elapsed:     time.Second,
+			retry:       false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			retry, wait := c.policy.ShouldRetry(c.req, c.resp, errors.New("failed"), c.attempts, c.elapsed)
+			if retry != c.retry {
+				t.Errorf("ShouldRetry() retry = %v, expected %v", retry, c.retry)
+			}
+			if c.wait != 0 && wait != c.wait {
+				t.Errorf("ShouldRetry() wait = %s, expected %s", wait, c.wait)
+			}
+		})
+	}
+}
+
+func TestDefaultRetryPolicyShouldRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "https://fooshop.myshopify.com/foo", nil)
+
+	policy := DefaultRetryPolicy{MaxAttempts: 3}
+	if retry, _ := policy.ShouldRetry(req, nil, ctx.Err(), 1, 0); retry {
+		t.Errorf("ShouldRetry() retried a cancelled request")
+	}
+}
+
+func TestDefaultRetryPolicyBackoff(t *testing.T) {
+	policy := DefaultRetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	cases := []struct {
+		attempts int
+		ceiling  time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{10, time.Second},
+	}
+
+	for _, c := range cases {
+		for i := 0; i < 100; i++ {
+			if wait := policy.backoff(c.attempts); wait < 0 || wait > c.ceiling {
+				t.Fatalf("backoff(%d) = %s, expected between 0 and %s", c.attempts, wait, c.ceiling)
+			}
+		}
+	}
+}
+
+func TestDoRetryPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	WithRetryPolicy(&DefaultRetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})(client)
+
+	cases := []struct {
+		method    string
+		relPath   string
+		responder httpmock.Responder
+		attempts  int
+	}{
+		{"GET", "foo/1", httpmock.NewErrorResponder(errors.New("connection reset")), 3},
+		{"GET", "foo/2", httpmock.NewStringResponder(http.StatusGatewayTimeout, ""), 3},
+		{"POST", "foo/3", httpmock.NewErrorResponder(errors.New("connection reset")), 1},
+		{"POST", "foo/4", httpmock.NewStringResponder(http.StatusInternalServerError, ""), 1},
+	}
+
+	for _, c := range cases {
+		shopUrl := fmt.Sprintf("https://fooshop.myshopify.com/%s", c.relPath)
+		httpmock.RegisterResponder(c.method, shopUrl, c.responder)
+
+		req, err := client.NewRequest(context.Background(), c.method, c.relPath, nil, nil)
+		if err != nil {
+			t.Fatal("error creating request: ", err)
+		}
+
+		if err = client.Do(req, nil); err == nil {
+			t.Errorf("Do(%s %s): expected error", c.method, c.relPath)
+		}
+
+		if calls := httpmock.GetCallCountInfo()[c.method+" "+shopUrl]; calls != c.attempts {
+			t.Errorf("Do(%s %s): made %d attempts, expected %d", c.method, c.relPath, calls, c.attempts)
+		}
+	}
+}
+
+func TestDoRetryContextCancelled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", "https://fooshop.myshopify.com/foo/1",
+		createResponderWithHeaders(http.StatusTooManyRequests, `{"errors":"Exceeded 2 calls per second for api client."}`, map[string]string{
+			"Retry-After": "10.0",
+		}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := client.NewRequest(ctx, "GET", "foo/1", nil, nil)
+	if err != nil {
+		t.Fatal("error creating request: ", err)
+	}
+
+	start := time.Now()
+	err = client.Do(req, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do(): expected error %v, actual %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Do(): waited %s after the context was done", elapsed)
+	}
+}
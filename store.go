@@ -0,0 +1,101 @@
+package goshopify
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// valueStore keeps values by key in memory or, when it has a path, in a JSON
+// file readable only by the current user. Every change of a file rewrites the
+// whole file. It is the implementation of the memory and file TokenStore,
+// CheckpointStore and SyncStateStore, and is safe for concurrent use.
+type valueStore[V any] struct {
+	mu sync.Mutex
+
+	// path of the JSON file, values are kept in memory when empty
+	path   string
+	values map[string]V
+
+	// notFound is returned by load for missing keys
+	notFound error
+}
+
+// newMemoryValueStore returns an empty valueStore keeping values in memory.
+func newMemoryValueStore[V any](notFound error) *valueStore[V] {
+	return &valueStore[V]{values: make(map[string]V), notFound: notFound}
+}
+
+// newFileValueStore returns a valueStore using the JSON file at path, which is
+// created on the first save.
+func newFileValueStore[V any](path string, notFound error) *valueStore[V] {
+	return &valueStore[V]{path: path, notFound: notFound}
+}
+
+// load returns the value of key or the store's notFound error.
+func (s *valueStore[V]) load(key string) (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var value V
+	values, err := s.read()
+	if err != nil {
+		return value, err
+	}
+
+	value, ok := values[key]
+	if !ok {
+		return value, s.notFound
+	}
+	return value, nil
+}
+
+func (s *valueStore[V]) save(key string, value V) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.read()
+	if err != nil {
+		return err
+	}
+	values[key] = value
+	return s.write(values)
+}
+
+func (s *valueStore[V]) delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.read()
+	if err != nil {
+		return err
+	}
+	delete(values, key)
+	return s.write(values)
+}
+
+// read returns the values of the store. Callers must hold s.mu.
+func (s *valueStore[V]) read() (map[string]V, error) {
+	if s.path == "" {
+		return s.values, nil
+	}
+
+	values := make(map[string]V)
+	if err := readJSONFile(s.path, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// write replaces the values of the store. Callers must hold s.mu.
+func (s *valueStore[V]) write(values map[string]V) error {
+	if s.path == "" {
+		s.values = values
+		return nil
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, b, 0600)
+}
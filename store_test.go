@@ -0,0 +1,90 @@
+package goshopify
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValueStore(t *testing.T) {
+	errMissing := errors.New("missing")
+	dir := t.TempDir()
+
+	cases := []struct {
+		description string
+		path        string
+		exists      bool
+		contents    string
+		invalid     bool
+	}{
+		{description: "memory"},
+		{description: "new file", path: filepath.Join(dir, "new.json")},
+		{description: "empty file", path: filepath.Join(dir, "empty.json"), exists: true},
+		{description: "invalid file", path: filepath.Join(dir, "invalid.json"), exists: true, contents: "not json", invalid: true},
+	}
+
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	state := SyncState{UpdatedAtMin: updatedAt, Seen: map[uint64]time.Time{42: updatedAt}}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			store := newMemoryValueStore[SyncState](errMissing)
+			if c.path != "" {
+				if c.exists {
+					if err := ioutil.WriteFile(c.path, []byte(c.contents), 0600); err != nil {
+						t.Fatal(err)
+					}
+				}
+				store = newFileValueStore[SyncState](c.path, errMissing)
+			}
+
+			_, err := store.load("products")
+			if c.invalid {
+				if err == nil || errors.Is(err, errMissing) {
+					t.Errorf("load of an invalid file returned %v, expected a decoding error", err)
+				}
+				return
+			}
+			if !errors.Is(err, errMissing) {
+				t.Errorf("load returned %v, expected the not found error", err)
+			}
+
+			if err := store.save("products", state); err != nil {
+				t.Fatalf("save returned error: %v", err)
+			}
+			if err := store.save("orders", SyncState{UpdatedAtMin: updatedAt}); err != nil {
+				t.Fatalf("save returned error: %v", err)
+			}
+			if loaded, err := store.load("products"); err != nil || !reflect.DeepEqual(loaded, state) {
+				t.Errorf("load returned %#v, %v, expected %#v", loaded, err, state)
+			}
+
+			if err := store.delete("products"); err != nil {
+				t.Fatalf("delete returned error: %v", err)
+			}
+			if _, err := store.load("products"); !errors.Is(err, errMissing) {
+				t.Errorf("load after delete returned %v, expected the not found error", err)
+			}
+
+			if c.path == "" {
+				return
+			}
+
+			// values persist across stores of the file, which only the user can read
+			if loaded, err := newFileValueStore[SyncState](c.path, errMissing).load("orders"); err != nil || !loaded.UpdatedAtMin.Equal(updatedAt) {
+				t.Errorf("load from a new store returned %#v, %v", loaded, err)
+			}
+			info, err := os.Stat(c.path)
+			if err != nil {
+				t.Fatalf("file not written: %v", err)
+			}
+			if info.Mode().Perm() != 0600 {
+				t.Errorf("file mode = %v, expected 0600", info.Mode().Perm())
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	storefrontApiPathPrefix = "api"
+	storefrontTokenHeader   = "X-Shopify-Storefront-Access-Token"
+	storefrontBuyerIPHeader = "Shopify-Storefront-Buyer-IP"
+)
+
+// StorefrontClient makes requests to the Storefront GraphQL API of a shop on
+// behalf of its buyers.
+// See: https://shopify.dev/docs/api/storefront
+type StorefrontClient struct {
+	client *Client
+
+	GraphQL GraphQLService
+	Cart    StorefrontCartService
+	Product StorefrontProductService
+}
+
+// NewStorefrontClient returns a new Storefront API client for the shop, with
+// a storefront access token, e.g. one created by StorefrontAccessTokenService.
+// The Storefront API has no stable version, the version of the API must be
+// set with WithVersion. Queries are sent with the same transport, retries,
+// throttling and error handling as the Admin GraphQL API, see GraphQLService.
+func NewStorefrontClient(shopName, token string, opts ...Option) (*StorefrontClient, error) {
+	c, err := NewClient(App{}, shopName, "", opts...)
+	if err != nil {
+		return nil, err
+	}
+	if c.apiVersion == defaultApiVersion || (!c.skipApiVersionCheck && !validApiVersion(c.apiVersion)) {
+		return nil, fmt.Errorf("storefront api requires an api version, use WithVersion")
+	}
+
+	c.storefrontToken = token
+	c.pathPrefix = fmt.Sprintf("%s/%s", storefrontApiPathPrefix, c.apiVersion)
+
+	s := &StorefrontClient{client: c, GraphQL: c.GraphQL}
+	s.Cart = &StorefrontCartServiceOp{client: s}
+	s.Product = &StorefrontProductServiceOp{client: s}
+	return s, nil
+}
+
+// GetRateLimits returns the GraphQL cost of the last query of the storefront
+// client, see Client.GetRateLimits.
+func (s *StorefrontClient) GetRateLimits() RateLimitInfo {
+	return s.client.GetRateLimits()
+}
+
+type buyerIPContextKey struct{}
+
+// ContextWithBuyerIP returns a context sending the IP address of the buyer
+// along with the Storefront API requests made with it, so that Shopify
+// applies its bot protection and rate limits to the buyer rather than to the
+// server making the requests.
+func ContextWithBuyerIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, buyerIPContextKey{}, ip)
+}
+
+// buyerIPFromContext returns the buyer IP set by ContextWithBuyerIP
+func buyerIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(buyerIPContextKey{}).(string)
+	return ip
+}
+
+// StorefrontMoney is an amount of money in a currency.
+type StorefrontMoney struct {
+	Amount       decimal.Decimal `json:"amount"`
+	CurrencyCode string          `json:"currencyCode"`
+}
+
+// StorefrontAttribute is a custom key value attribute of a cart or cart line.
+type StorefrontAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// StorefrontSelectedOption is the option value of a product variant.
+type StorefrontSelectedOption struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+const storefrontMoneyFields = `amount currencyCode`
+
+const storefrontVariantFields = `
+	id
+	title
+	sku
+	availableForSale
+	selectedOptions { name value }
+	price { ` + storefrontMoneyFields + ` }
+	compareAtPrice { ` + storefrontMoneyFields + ` }
+`
+
+// StorefrontProductVariant represents a product variant as seen by buyers.
+type StorefrontProductVariant struct {
+	Id               string                     `json:"id"`
+	Title            string                     `json:"title"`
+	Sku              string                     `json:"sku"`
+	AvailableForSale bool                       `json:"availableForSale"`
+	SelectedOptions  []StorefrontSelectedOption `json:"selectedOptions"`
+	Price            StorefrontMoney            `json:"price"`
+	CompareAtPrice   *StorefrontMoney           `json:"compareAtPrice"`
+}
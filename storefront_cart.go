@@ -0,0 +1,218 @@
+package goshopify
+
+import (
+	"context"
+	"time"
+)
+
+// StorefrontCartService is an interface for interfacing with the carts of the
+// Storefront API. Mutations returning userErrors return them as
+// GraphQLUserErrors.
+// See: https://shopify.dev/docs/api/storefront/latest/objects/Cart
+type StorefrontCartService interface {
+	Create(context.Context, StorefrontCartInput) (*StorefrontCart, error)
+	Get(context.Context, string) (*StorefrontCart, error)
+	AddLines(context.Context, string, []StorefrontCartLineInput) (*StorefrontCart, error)
+	UpdateLines(context.Context, string, []StorefrontCartLineUpdateInput) (*StorefrontCart, error)
+	RemoveLines(context.Context, string, []string) (*StorefrontCart, error)
+}
+
+// StorefrontCartServiceOp handles communication with the cart related queries
+// of the Storefront API.
+type StorefrontCartServiceOp struct {
+	client *StorefrontClient
+}
+
+// StorefrontCart represents the cart of a buyer
+type StorefrontCart struct {
+	Id            string                       `json:"id"`
+	CheckoutUrl   string                       `json:"checkoutUrl"`
+	Note          string                       `json:"note"`
+	TotalQuantity int                          `json:"totalQuantity"`
+	Attributes    []StorefrontAttribute        `json:"attributes"`
+	Cost          StorefrontCartCost           `json:"cost"`
+	Lines         StorefrontCartLineConnection `json:"lines"`
+	CreatedAt     *time.Time                   `json:"createdAt"`
+	UpdatedAt     *time.Time                   `json:"updatedAt"`
+}
+
+// StorefrontCartCost is the estimated cost of a cart
+type StorefrontCartCost struct {
+	SubtotalAmount StorefrontMoney  `json:"subtotalAmount"`
+	TotalAmount    StorefrontMoney  `json:"totalAmount"`
+	TotalTaxAmount *StorefrontMoney `json:"totalTaxAmount"`
+}
+
+// StorefrontCartLineConnection holds the lines of a cart, up to the first 250
+type StorefrontCartLineConnection struct {
+	Nodes []StorefrontCartLine `json:"nodes"`
+}
+
+// StorefrontCartLine is a product variant in a cart
+type StorefrontCartLine struct {
+	Id          string                   `json:"id"`
+	Quantity    int                      `json:"quantity"`
+	Attributes  []StorefrontAttribute    `json:"attributes"`
+	Merchandise StorefrontProductVariant `json:"merchandise"`
+	Cost        StorefrontCartLineCost   `json:"cost"`
+}
+
+// StorefrontCartLineCost is the estimated cost of a cart line
+type StorefrontCartLineCost struct {
+	AmountPerQuantity StorefrontMoney `json:"amountPerQuantity"`
+	TotalAmount       StorefrontMoney `json:"totalAmount"`
+}
+
+// StorefrontCartInput is the input of a new cart
+type StorefrontCartInput struct {
+	Lines         []StorefrontCartLineInput         `json:"lines,omitempty"`
+	Attributes    []StorefrontAttribute             `json:"attributes,omitempty"`
+	Note          string                            `json:"note,omitempty"`
+	DiscountCodes []string                          `json:"discountCodes,omitempty"`
+	BuyerIdentity *StorefrontCartBuyerIdentityInput `json:"buyerIdentity,omitempty"`
+}
+
+// StorefrontCartBuyerIdentityInput identifies the buyer of a cart, which
+// decides its currency, prices and taxes
+type StorefrontCartBuyerIdentityInput struct {
+	Email               string `json:"email,omitempty"`
+	Phone               string `json:"phone,omitempty"`
+	CountryCode         string `json:"countryCode,omitempty"`
+	CustomerAccessToken string `json:"customerAccessToken,omitempty"`
+}
+
+// StorefrontCartLineInput is a product variant to add to a cart
+type StorefrontCartLineInput struct {
+	MerchandiseId string                `json:"merchandiseId"`
+	Quantity      int                   `json:"quantity,omitempty"`
+	Attributes    []StorefrontAttribute `json:"attributes,omitempty"`
+	SellingPlanId string                `json:"sellingPlanId,omitempty"`
+}
+
+// StorefrontCartLineUpdateInput updates a line of a cart, a quantity of 0
+// removes the line
+type StorefrontCartLineUpdateInput struct {
+	Id            string                `json:"id"`
+	Quantity      *int                  `json:"quantity,omitempty"`
+	MerchandiseId string                `json:"merchandiseId,omitempty"`
+	Attributes    []StorefrontAttribute `json:"attributes,omitempty"`
+}
+
+const storefrontCartFields = `
+	id
+	checkoutUrl
+	note
+	totalQuantity
+	createdAt
+	updatedAt
+	attributes { key value }
+	cost {
+		subtotalAmount { ` + storefrontMoneyFields + ` }
+		totalAmount { ` + storefrontMoneyFields + ` }
+		totalTaxAmount { ` + storefrontMoneyFields + ` }
+	}
+	lines(first: 250) {
+		nodes {
+			id
+			quantity
+			attributes { key value }
+			merchandise { ... on ProductVariant { ` + storefrontVariantFields + ` } }
+			cost {
+				amountPerQuantity { ` + storefrontMoneyFields + ` }
+				totalAmount { ` + storefrontMoneyFields + ` }
+			}
+		}
+	}
+`
+
+// storefrontCartPayload is the payload of the cart mutations
+type storefrontCartPayload struct {
+	Cart *StorefrontCart `json:"cart"`
+}
+
+// Create a cart
+func (s *StorefrontCartServiceOp) Create(ctx context.Context, input StorefrontCartInput) (*StorefrontCart, error) {
+	m := `mutation cartCreate($input: CartInput!) {
+		cartCreate(input: $input) {
+			cart { ` + storefrontCartFields + ` }
+			userErrors { field message code }
+		}
+	}`
+
+	resp := struct {
+		CartCreate storefrontCartPayload `json:"cartCreate"`
+	}{}
+	err := s.client.GraphQL.Mutate(ctx, m, map[string]interface{}{"input": input}, &resp)
+	return resp.CartCreate.Cart, err
+}
+
+// Get a cart by its id, returns ErrNotFound when the cart doesn't exist or
+// was completed
+func (s *StorefrontCartServiceOp) Get(ctx context.Context, cartId string) (*StorefrontCart, error) {
+	q := `query cart($id: ID!) {
+		cart(id: $id) { ` + storefrontCartFields + ` }
+	}`
+
+	resp := struct {
+		Cart *StorefrontCart `json:"cart"`
+	}{}
+	if err := s.client.GraphQL.Query(ctx, q, map[string]interface{}{"id": cartId}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Cart == nil {
+		return nil, ErrNotFound
+	}
+	return resp.Cart, nil
+}
+
+// AddLines adds product variants to a cart
+func (s *StorefrontCartServiceOp) AddLines(ctx context.Context, cartId string, lines []StorefrontCartLineInput) (*StorefrontCart, error) {
+	m := `mutation cartLinesAdd($cartId: ID!, $lines: [CartLineInput!]!) {
+		cartLinesAdd(cartId: $cartId, lines: $lines) {
+			cart { ` + storefrontCartFields + ` }
+			userErrors { field message code }
+		}
+	}`
+
+	resp := struct {
+		CartLinesAdd storefrontCartPayload `json:"cartLinesAdd"`
+	}{}
+	vars := map[string]interface{}{"cartId": cartId, "lines": lines}
+	err := s.client.GraphQL.Mutate(ctx, m, vars, &resp)
+	return resp.CartLinesAdd.Cart, err
+}
+
+// UpdateLines updates the quantity, merchandise or attributes of lines of a
+// cart
+func (s *StorefrontCartServiceOp) UpdateLines(ctx context.Context, cartId string, lines []StorefrontCartLineUpdateInput) (*StorefrontCart, error) {
+	m := `mutation cartLinesUpdate($cartId: ID!, $lines: [CartLineUpdateInput!]!) {
+		cartLinesUpdate(cartId: $cartId, lines: $lines) {
+			cart { ` + storefrontCartFields + ` }
+			userErrors { field message code }
+		}
+	}`
+
+	resp := struct {
+		CartLinesUpdate storefrontCartPayload `json:"cartLinesUpdate"`
+	}{}
+	vars := map[string]interface{}{"cartId": cartId, "lines": lines}
+	err := s.client.GraphQL.Mutate(ctx, m, vars, &resp)
+	return resp.CartLinesUpdate.Cart, err
+}
+
+// RemoveLines removes lines from a cart by their id
+func (s *StorefrontCartServiceOp) RemoveLines(ctx context.Context, cartId string, lineIds []string) (*StorefrontCart, error) {
+	m := `mutation cartLinesRemove($cartId: ID!, $lineIds: [ID!]!) {
+		cartLinesRemove(cartId: $cartId, lineIds: $lineIds) {
+			cart { ` + storefrontCartFields + ` }
+			userErrors { field message code }
+		}
+	}`
+
+	resp := struct {
+		CartLinesRemove storefrontCartPayload `json:"cartLinesRemove"`
+	}{}
+	vars := map[string]interface{}{"cartId": cartId, "lineIds": lineIds}
+	err := s.client.GraphQL.Mutate(ctx, m, vars, &resp)
+	return resp.CartLinesRemove.Cart, err
+}
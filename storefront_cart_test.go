@@ -0,0 +1,169 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/shopspring/decimal"
+)
+
+const storefrontCartJSON = `{
+	"id":"gid://shopify/Cart/c1",
+	"checkoutUrl":"https://fooshop.myshopify.com/cart/c/c1",
+	"note":"gift",
+	"totalQuantity":2,
+	"attributes":[{"key":"source","value":"app"}],
+	"cost":{
+		"subtotalAmount":{"amount":"20.0","currencyCode":"CAD"},
+		"totalAmount":{"amount":"22.6","currencyCode":"CAD"},
+		"totalTaxAmount":null
+	},
+	"lines":{"nodes":[{
+		"id":"gid://shopify/CartLine/l1",
+		"quantity":2,
+		"attributes":[],
+		"merchandise":{"id":"gid://shopify/ProductVariant/1","title":"Small","sku":"S","availableForSale":true,
+			"selectedOptions":[{"name":"Size","value":"Small"}],"price":{"amount":"10.0","currencyCode":"CAD"},"compareAtPrice":null},
+		"cost":{"amountPerQuantity":{"amount":"10.0","currencyCode":"CAD"},"totalAmount":{"amount":"20.0","currencyCode":"CAD"}}
+	}]}
+}`
+
+// registerStorefrontGraphQL responds to the storefront queries with the data
+// returned by respond, after recording the variables of the query
+func registerStorefrontGraphQL(respond func(operation string, vars map[string]interface{}) string) {
+	httpmock.RegisterResponder("POST", storefrontGraphQLUrl,
+		func(req *http.Request) (*http.Response, error) {
+			body := struct {
+				Query     string                 `json:"query"`
+				Variables map[string]interface{} `json:"variables"`
+			}{}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			return httpmock.NewStringResponse(200, `{"data":`+respond(graphQLOperationName(body.Query), body.Variables)+`}`), nil
+		},
+	)
+}
+
+func TestStorefrontCartCreate(t *testing.T) {
+	storefront := setupStorefront()
+	defer teardown()
+
+	var input interface{}
+	registerStorefrontGraphQL(func(operation string, vars map[string]interface{}) string {
+		if operation != "cartCreate" {
+			t.Errorf("Cart.Create ran %s", operation)
+		}
+		input = vars["input"]
+		return `{"cartCreate":{"cart":` + storefrontCartJSON + `,"userErrors":[]}}`
+	})
+
+	cart, err := storefront.Cart.Create(context.Background(), StorefrontCartInput{
+		Lines:         []StorefrontCartLineInput{{MerchandiseId: "gid://shopify/ProductVariant/1", Quantity: 2}},
+		Note:          "gift",
+		BuyerIdentity: &StorefrontCartBuyerIdentityInput{CountryCode: "CA"},
+	})
+	if err != nil {
+		t.Fatalf("Cart.Create returned error: %v", err)
+	}
+
+	expectedInput := map[string]interface{}{
+		"lines":         []interface{}{map[string]interface{}{"merchandiseId": "gid://shopify/ProductVariant/1", "quantity": float64(2)}},
+		"note":          "gift",
+		"buyerIdentity": map[string]interface{}{"countryCode": "CA"},
+	}
+	if !reflect.DeepEqual(input, expectedInput) {
+		t.Errorf("Cart.Create sent input %v, expected %v", input, expectedInput)
+	}
+
+	if cart.Id != "gid://shopify/Cart/c1" || cart.TotalQuantity != 2 || cart.Cost.TotalTaxAmount != nil ||
+		!cart.Cost.TotalAmount.Amount.Equal(decimal.RequireFromString("22.6")) {
+		t.Errorf("Cart.Create returned %+v", cart)
+	}
+	if len(cart.Lines.Nodes) != 1 {
+		t.Fatalf("Cart.Create returned lines %+v", cart.Lines)
+	}
+	line := cart.Lines.Nodes[0]
+	if line.Merchandise.Sku != "S" || line.Merchandise.CompareAtPrice != nil || !line.Cost.TotalAmount.Amount.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Cart.Create returned line %+v", line)
+	}
+}
+
+func TestStorefrontCartLines(t *testing.T) {
+	storefront := setupStorefront()
+	defer teardown()
+
+	var operations []string
+	registerStorefrontGraphQL(func(operation string, vars map[string]interface{}) string {
+		operations = append(operations, operation)
+		if vars["cartId"] != "gid://shopify/Cart/c1" {
+			t.Errorf("%s sent cart id %v", operation, vars["cartId"])
+		}
+		return `{"` + operation + `":{"cart":` + storefrontCartJSON + `,"userErrors":[]}}`
+	})
+
+	ctx := context.Background()
+	if _, err := storefront.Cart.AddLines(ctx, "gid://shopify/Cart/c1", []StorefrontCartLineInput{{MerchandiseId: "gid://shopify/ProductVariant/1"}}); err != nil {
+		t.Errorf("Cart.AddLines returned error: %v", err)
+	}
+	quantity := 0
+	if _, err := storefront.Cart.UpdateLines(ctx, "gid://shopify/Cart/c1", []StorefrontCartLineUpdateInput{{Id: "gid://shopify/CartLine/l1", Quantity: &quantity}}); err != nil {
+		t.Errorf("Cart.UpdateLines returned error: %v", err)
+	}
+	if _, err := storefront.Cart.RemoveLines(ctx, "gid://shopify/Cart/c1", []string{"gid://shopify/CartLine/l1"}); err != nil {
+		t.Errorf("Cart.RemoveLines returned error: %v", err)
+	}
+
+	expected := []string{"cartLinesAdd", "cartLinesUpdate", "cartLinesRemove"}
+	if !reflect.DeepEqual(operations, expected) {
+		t.Errorf("ran %v, expected %v", operations, expected)
+	}
+}
+
+func TestStorefrontCartUserErrors(t *testing.T) {
+	storefront := setupStorefront()
+	defer teardown()
+
+	registerStorefrontGraphQL(func(string, map[string]interface{}) string {
+		return `{"cartLinesUpdate":{"cart":null,"userErrors":[{"field":["lines","0","quantity"],"message":"The quantity must be positive","code":"INVALID"}]}}`
+	})
+
+	quantity := -1
+	cart, err := storefront.Cart.UpdateLines(context.Background(), "gid://shopify/Cart/c1", []StorefrontCartLineUpdateInput{{Id: "gid://shopify/CartLine/l1", Quantity: &quantity}})
+	var userErrors GraphQLUserErrors
+	if !errors.As(err, &userErrors) || !userErrors.HasCode("INVALID") {
+		t.Fatalf("Cart.UpdateLines returned %v, expected GraphQLUserErrors", err)
+	}
+	if cart != nil {
+		t.Errorf("Cart.UpdateLines returned %+v, expected nil", cart)
+	}
+}
+
+func TestStorefrontCartGet(t *testing.T) {
+	storefront := setupStorefront()
+	defer teardown()
+
+	registerStorefrontGraphQL(func(operation string, vars map[string]interface{}) string {
+		if vars["id"] == "gid://shopify/Cart/c1" {
+			return `{"cart":` + storefrontCartJSON + `}`
+		}
+		return `{"cart":null}`
+	})
+
+	cart, err := storefront.Cart.Get(context.Background(), "gid://shopify/Cart/c1")
+	if err != nil {
+		t.Fatalf("Cart.Get returned error: %v", err)
+	}
+	if cart.CheckoutUrl != "https://fooshop.myshopify.com/cart/c/c1" {
+		t.Errorf("Cart.Get returned %+v", cart)
+	}
+
+	if _, err := storefront.Cart.Get(context.Background(), "gid://shopify/Cart/completed"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Cart.Get returned %v, expected ErrNotFound", err)
+	}
+}
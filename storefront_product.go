@@ -0,0 +1,100 @@
+package goshopify
+
+import (
+	"context"
+	"time"
+)
+
+// StorefrontProductService is an interface for interfacing with the products
+// of the Storefront API.
+// See: https://shopify.dev/docs/api/storefront/latest/objects/Product
+type StorefrontProductService interface {
+	GetByHandle(context.Context, string) (*StorefrontProduct, error)
+}
+
+// StorefrontProductServiceOp handles communication with the product related
+// queries of the Storefront API.
+type StorefrontProductServiceOp struct {
+	client *StorefrontClient
+}
+
+// StorefrontProduct represents a product as seen by buyers, with up to its
+// first 250 variants
+type StorefrontProduct struct {
+	Id               string                             `json:"id"`
+	Handle           string                             `json:"handle"`
+	Title            string                             `json:"title"`
+	Description      string                             `json:"description"`
+	DescriptionHtml  string                             `json:"descriptionHtml"`
+	Vendor           string                             `json:"vendor"`
+	ProductType      string                             `json:"productType"`
+	Tags             []string                           `json:"tags"`
+	AvailableForSale bool                               `json:"availableForSale"`
+	OnlineStoreUrl   string                             `json:"onlineStoreUrl"`
+	Options          []StorefrontProductOption          `json:"options"`
+	PriceRange       StorefrontProductPriceRange        `json:"priceRange"`
+	Variants         StorefrontProductVariantConnection `json:"variants"`
+	CreatedAt        *time.Time                         `json:"createdAt"`
+	UpdatedAt        *time.Time                         `json:"updatedAt"`
+	PublishedAt      *time.Time                         `json:"publishedAt"`
+}
+
+// StorefrontProductOption is an option of a product, e.g. its size
+type StorefrontProductOption struct {
+	Id     string   `json:"id"`
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// StorefrontProductPriceRange is the range of the prices of the variants of a
+// product
+type StorefrontProductPriceRange struct {
+	MinVariantPrice StorefrontMoney `json:"minVariantPrice"`
+	MaxVariantPrice StorefrontMoney `json:"maxVariantPrice"`
+}
+
+// StorefrontProductVariantConnection holds the variants of a product
+type StorefrontProductVariantConnection struct {
+	Nodes []StorefrontProductVariant `json:"nodes"`
+}
+
+const storefrontProductFields = `
+	id
+	handle
+	title
+	description
+	descriptionHtml
+	vendor
+	productType
+	tags
+	availableForSale
+	onlineStoreUrl
+	createdAt
+	updatedAt
+	publishedAt
+	options { id name values }
+	priceRange {
+		minVariantPrice { ` + storefrontMoneyFields + ` }
+		maxVariantPrice { ` + storefrontMoneyFields + ` }
+	}
+	variants(first: 250) { nodes { ` + storefrontVariantFields + ` } }
+`
+
+// GetByHandle gets a product by its handle, returns ErrNotFound when no
+// product published to the storefront has the handle
+func (s *StorefrontProductServiceOp) GetByHandle(ctx context.Context, handle string) (*StorefrontProduct, error) {
+	q := `query productByHandle($handle: String!) {
+		product(handle: $handle) { ` + storefrontProductFields + ` }
+	}`
+
+	resp := struct {
+		Product *StorefrontProduct `json:"product"`
+	}{}
+	if err := s.client.GraphQL.Query(ctx, q, map[string]interface{}{"handle": handle}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Product == nil {
+		return nil, ErrNotFound
+	}
+	return resp.Product, nil
+}
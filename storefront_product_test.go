@@ -0,0 +1,60 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestStorefrontProductGetByHandle(t *testing.T) {
+	storefront := setupStorefront()
+	defer teardown()
+
+	registerStorefrontGraphQL(func(operation string, vars map[string]interface{}) string {
+		if operation != "productByHandle" {
+			t.Errorf("Product.GetByHandle ran %s", operation)
+		}
+		if vars["handle"] != "shirt" {
+			return `{"product":null}`
+		}
+		return `{"product":{
+			"id":"gid://shopify/Product/1",
+			"handle":"shirt",
+			"title":"Shirt",
+			"tags":["summer"],
+			"availableForSale":true,
+			"onlineStoreUrl":null,
+			"createdAt":"2024-01-02T03:04:05Z",
+			"options":[{"id":"gid://shopify/ProductOption/1","name":"Size","values":["Small","Large"]}],
+			"priceRange":{"minVariantPrice":{"amount":"10.0","currencyCode":"CAD"},"maxVariantPrice":{"amount":"12.5","currencyCode":"CAD"}},
+			"variants":{"nodes":[
+				{"id":"gid://shopify/ProductVariant/1","title":"Small","sku":"S","availableForSale":true,"price":{"amount":"10.0","currencyCode":"CAD"}},
+				{"id":"gid://shopify/ProductVariant/2","title":"Large","sku":null,"availableForSale":false,"price":{"amount":"12.5","currencyCode":"CAD"}}
+			]}
+		}}`
+	})
+
+	product, err := storefront.Product.GetByHandle(context.Background(), "shirt")
+	if err != nil {
+		t.Fatalf("Product.GetByHandle returned error: %v", err)
+	}
+
+	if product.Id != "gid://shopify/Product/1" || product.Title != "Shirt" || !reflect.DeepEqual(product.Tags, []string{"summer"}) ||
+		product.OnlineStoreUrl != "" || product.CreatedAt == nil {
+		t.Errorf("Product.GetByHandle returned %+v", product)
+	}
+	if !product.PriceRange.MaxVariantPrice.Amount.Equal(decimal.RequireFromString("12.5")) {
+		t.Errorf("Product.GetByHandle returned price range %+v", product.PriceRange)
+	}
+	variants := product.Variants.Nodes
+	if len(variants) != 2 || variants[0].Sku != "S" || variants[1].AvailableForSale {
+		t.Errorf("Product.GetByHandle returned variants %+v", variants)
+	}
+
+	if _, err := storefront.Product.GetByHandle(context.Background(), "unpublished"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Product.GetByHandle returned %v, expected ErrNotFound", err)
+	}
+}
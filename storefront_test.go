@@ -0,0 +1,117 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+const storefrontGraphQLUrl = "https://fooshop.myshopify.com/api/" + testApiVersion + "/graphql.json"
+
+// setupStorefront returns a storefront client of the fooshop mocked by
+// httpmock, tear it down with teardown
+func setupStorefront() *StorefrontClient {
+	storefront, err := NewStorefrontClient("fooshop", "storefronttoken", WithVersion(testApiVersion), skipApiVersionCheck(), WithRetry(maxRetries))
+	if err != nil {
+		panic(err)
+	}
+	httpmock.ActivateNonDefault(storefront.client.Client)
+	return storefront
+}
+
+func TestNewStorefrontClient(t *testing.T) {
+	storefront, err := NewStorefrontClient("fooshop", "storefronttoken", WithVersion(UnstableApiVersion))
+	if err != nil {
+		t.Fatalf("NewStorefrontClient returned error: %v", err)
+	}
+	if storefront.client.pathPrefix != "api/unstable" {
+		t.Errorf("storefront client path prefix = %s, expected api/unstable", storefront.client.pathPrefix)
+	}
+
+	for _, opts := range [][]Option{nil, {WithVersion("stable")}} {
+		_, err := NewStorefrontClient("fooshop", "storefronttoken", opts...)
+		if err == nil || err.Error() != "storefront api requires an api version, use WithVersion" {
+			t.Errorf("NewStorefrontClient without a version returned %v", err)
+		}
+	}
+	if _, err := NewStorefrontClient("fooshop", "storefronttoken", WithVersion("latest")); err == nil {
+		t.Error("NewStorefrontClient with an invalid version should return an error")
+	}
+}
+
+func TestStorefrontClientHeaders(t *testing.T) {
+	storefront := setupStorefront()
+	defer teardown()
+
+	var header http.Header
+	httpmock.RegisterResponder("POST", storefrontGraphQLUrl,
+		func(req *http.Request) (*http.Response, error) {
+			header = req.Header
+			return httpmock.NewStringResponse(200, `{"data":{"shop":{"name":"Foo shop"}}}`), nil
+		},
+	)
+
+	resp := struct {
+		Shop struct {
+			Name string `json:"name"`
+		} `json:"shop"`
+	}{}
+	if err := storefront.GraphQL.Query(context.Background(), "{ shop { name } }", nil, &resp); err != nil {
+		t.Fatalf("Storefront GraphQL.Query returned error: %v", err)
+	}
+	if resp.Shop.Name != "Foo shop" {
+		t.Errorf("Storefront GraphQL.Query returned shop %s", resp.Shop.Name)
+	}
+
+	if token := header.Get("X-Shopify-Storefront-Access-Token"); token != "storefronttoken" {
+		t.Errorf("X-Shopify-Storefront-Access-Token = %q, expected storefronttoken", token)
+	}
+	for _, name := range []string{"X-Shopify-Access-Token", "Authorization", "Shopify-Storefront-Buyer-IP"} {
+		if value := header.Get(name); value != "" {
+			t.Errorf("storefront request sent %s: %s", name, value)
+		}
+	}
+
+	ctx := ContextWithBuyerIP(context.Background(), "192.0.2.1")
+	if err := storefront.GraphQL.Query(ctx, "{ shop { name } }", nil, &resp); err != nil {
+		t.Fatalf("Storefront GraphQL.Query returned error: %v", err)
+	}
+	if ip := header.Get("Shopify-Storefront-Buyer-IP"); ip != "192.0.2.1" {
+		t.Errorf("Shopify-Storefront-Buyer-IP = %q, expected 192.0.2.1", ip)
+	}
+}
+
+func TestStorefrontClientErrors(t *testing.T) {
+	storefront := setupStorefront()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", storefrontGraphQLUrl,
+		httpmock.NewStringResponder(200, `{"errors":[{"message":"Field 'nope' doesn't exist on type 'QueryRoot'","extensions":{"code":"undefinedField"}}]}`))
+
+	err := storefront.GraphQL.Query(context.Background(), "{ nope }", nil, nil)
+	responseError, ok := err.(ResponseError)
+	if !ok {
+		t.Fatalf("Storefront GraphQL.Query returned %v, expected a ResponseError", err)
+	}
+	if errs := responseError.GetGraphQLErrors(); len(errs) != 1 || errs[0].Code() != "undefinedField" {
+		t.Errorf("Storefront GraphQL.Query returned graphql errors %v", errs)
+	}
+
+	// the admin client isn't affected by storefront clients
+	setup()
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("X-Shopify-Storefront-Access-Token") != "" || req.Header.Get("Shopify-Storefront-Buyer-IP") != "" {
+				t.Error("admin request sent storefront headers")
+			}
+			return httpmock.NewStringResponse(200, `{"data":{}}`), nil
+		},
+	)
+	ctx := ContextWithBuyerIP(context.Background(), "192.0.2.1")
+	if err := client.GraphQL.Query(ctx, "{ shop { name } }", nil, nil); err != nil {
+		t.Errorf("GraphQL.Query returned error: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// streamDecoder is implemented by resources decoding the response body while
+// it is read, instead of send decoding the whole body into them.
+type streamDecoder interface {
+	decodeStream(r io.Reader) error
+}
+
+// streamedResponseKey marks the context of requests decoded by a
+// streamDecoder, whose successful responses aren't read whole for logging
+type streamedResponseKey struct{}
+
+// logsResponseBody reports whether the body of a response is logged. The body
+// of a successful response to a streamed request isn't, since logging it would
+// hold the whole body in memory.
+func logsResponseBody(res *http.Response) bool {
+	if res.Request == nil || res.StatusCode >= http.StatusMultipleChoices {
+		return true
+	}
+	streamed, _ := res.Request.Context().Value(streamedResponseKey{}).(bool)
+	return !streamed
+}
+
+// listStream decodes the elements of the array under key in a response such as
+// {"orders": [...]} one at a time, other keys are skipped.
+type listStream struct {
+	key string
+
+	// decode is called with the decoder positioned at each element
+	decode func(*json.Decoder) error
+}
+
+func (s *listStream) decodeStream(r io.Reader) error {
+	decoder := json.NewDecoder(r)
+
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		if token != s.key {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// a missing list is sent as null
+		if err := expectDelim(decoder, '['); err == errNullToken {
+			continue
+		} else if err != nil {
+			return err
+		}
+		for decoder.More() {
+			if err := s.decode(decoder); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(decoder, ']'); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, '}')
+}
+
+// errNullToken is returned by expectDelim when the value is null
+var errNullToken = fmt.Errorf("unexpected null")
+
+// expectDelim reads the next token, which must be the delimiter.
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return errNullToken
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected token %v, expected %v", token, delim)
+	}
+	return nil
+}
+
+// StreamWithPagination performs a GET request for the given path and calls
+// decode for every element of the key array in the response while the body is
+// read, so that large pages are never held in memory at once. It returns the
+// pagination of the response.
+func (c *Client) StreamWithPagination(ctx context.Context, path, key string, options interface{}, decode func(*json.Decoder) error) (*Pagination, error) {
+	return c.ListWithPagination(ctx, path, &listStream{key: key, decode: decode}, options)
+}
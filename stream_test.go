@@ -0,0 +1,62 @@
+package goshopify
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListStreamDecode(t *testing.T) {
+	cases := []struct {
+		body     string
+		expected []uint64
+		err      bool
+	}{
+		{`{"orders": [{"id":1},{"id":2}]}`, []uint64{1, 2}, false},
+		{`{"count": 2, "orders": [{"id":1}], "other": {"orders": [{"id":3}]}}`, []uint64{1}, false},
+		{`{"orders": []}`, nil, false},
+		{`{"orders": null}`, nil, false},
+		{`{}`, nil, false},
+		{`[]`, nil, true},
+		{`{"orders": {"id":1}}`, nil, true},
+		{`{"orders": [{"id":1},`, []uint64{1}, true},
+	}
+
+	for _, c := range cases {
+		var ids []uint64
+		stream := &listStream{key: "orders", decode: func(decoder *json.Decoder) error {
+			var order Order
+			if err := decoder.Decode(&order); err != nil {
+				return err
+			}
+			ids = append(ids, order.Id)
+			return nil
+		}}
+
+		err := stream.decodeStream(strings.NewReader(c.body))
+		if (err != nil) != c.err {
+			t.Errorf("decodeStream(%s) returned error %v, expected error: %v", c.body, err, c.err)
+		}
+		if !reflect.DeepEqual(ids, c.expected) {
+			t.Errorf("decodeStream(%s) decoded %v, expected %v", c.body, ids, c.expected)
+		}
+	}
+}
+
+func TestListStreamDecodeStops(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	stream := &listStream{key: "orders", decode: func(decoder *json.Decoder) error {
+		calls++
+		return stop
+	}}
+
+	if err := stream.decodeStream(strings.NewReader(`{"orders": [{"id":1},{"id":2}]}`)); err != stop {
+		t.Errorf("decodeStream returned %v, expected %v", err, stop)
+	}
+	if calls != 1 {
+		t.Errorf("decode called %d times, expected 1", calls)
+	}
+}
@@ -0,0 +1,64 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// LogAttr is a typed attribute of a structured log entry.
+type LogAttr struct {
+	Key   string
+	Value interface{}
+}
+
+// StructuredLogger receives request and response log entries with typed
+// attributes such as the shop, method, path, status and Shopify's
+// X-Request-Id, see WithStructuredLogger. Levels are the Level constants used
+// by LeveledLogger.
+//
+// NewSlogLogger adapts a log/slog Handler and NewLeveledStructuredLogger
+// adapts a LeveledLoggerInterface.
+type StructuredLogger interface {
+	// Enabled reports whether entries of the level are logged, so that
+	// bodies are only read and redacted when they will be written.
+	Enabled(ctx context.Context, level int) bool
+
+	Log(ctx context.Context, level int, msg string, attrs ...LogAttr)
+}
+
+// NewLeveledStructuredLogger adapts a LeveledLoggerInterface to a
+// StructuredLogger, writing attributes as key=value pairs after the message.
+func NewLeveledStructuredLogger(logger LeveledLoggerInterface) StructuredLogger {
+	return &leveledStructuredLogger{log: logger}
+}
+
+type leveledStructuredLogger struct {
+	log LeveledLoggerInterface
+}
+
+func (l *leveledStructuredLogger) Enabled(_ context.Context, level int) bool {
+	if leveled, ok := l.log.(*LeveledLogger); ok {
+		return leveled.Level >= level
+	}
+	return true
+}
+
+func (l *leveledStructuredLogger) Log(_ context.Context, level int, msg string, attrs ...LogAttr) {
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, attr := range attrs {
+		fmt.Fprintf(&b, " %s=%v", attr.Key, attr.Value)
+	}
+
+	switch level {
+	case LevelError:
+		l.log.Errorf("%s", b.String())
+	case LevelWarn:
+		l.log.Warnf("%s", b.String())
+	case LevelInfo:
+		l.log.Infof("%s", b.String())
+	default:
+		l.log.Debugf("%s", b.String())
+	}
+}
@@ -0,0 +1,135 @@
+package goshopify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+type structuredEntry struct {
+	level int
+	msg   string
+	attrs map[string]interface{}
+}
+
+// recordingStructuredLogger keeps every entry it receives
+type recordingStructuredLogger struct {
+	mu      sync.Mutex
+	level   int
+	entries []structuredEntry
+}
+
+func (r *recordingStructuredLogger) Enabled(_ context.Context, level int) bool {
+	return r.level >= level
+}
+
+func (r *recordingStructuredLogger) Log(_ context.Context, level int, msg string, attrs ...LogAttr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry := structuredEntry{level: level, msg: msg, attrs: map[string]interface{}{}}
+	for _, attr := range attrs {
+		entry.attrs[attr.Key] = attr.Value
+	}
+	r.entries = append(r.entries, entry)
+}
+
+func TestStructuredLoggerRequestResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	logger := &recordingStructuredLogger{level: LevelDebug}
+	WithStructuredLogger(logger)(client)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/customers/1.json", client.pathPrefix),
+		createResponderWithHeaders(200, `{"customer":{"id":1,"email":"a@b.c"}}`, map[string]string{
+			"X-Request-Id": "req-1",
+		}))
+
+	if _, err := client.Customer.Get(context.Background(), 1, nil); err != nil {
+		t.Fatalf("Customer.Get returned error: %v", err)
+	}
+
+	if len(logger.entries) != 2 {
+		t.Fatalf("expected a request and response entry, got %#v", logger.entries)
+	}
+
+	path := fmt.Sprintf("/%s/customers/1.json", client.pathPrefix)
+	request := logger.entries[0]
+	expectedRequest := structuredEntry{
+		level: LevelDebug,
+		msg:   "shopify request",
+		attrs: map[string]interface{}{"shop": "fooshop.myshopify.com", "method": "GET", "path": path},
+	}
+	if !reflect.DeepEqual(request, expectedRequest) {
+		t.Errorf("request entry = %#v, expected %#v", request, expectedRequest)
+	}
+
+	response := logger.entries[1]
+	expectedResponse := structuredEntry{
+		level: LevelDebug,
+		msg:   "shopify response",
+		attrs: map[string]interface{}{
+			"shop":       "fooshop.myshopify.com",
+			"method":     "GET",
+			"path":       path,
+			"status":     200,
+			"request_id": "req-1",
+			"body":       `{"customer":{"email":"[REDACTED]","id":1}}`,
+		},
+	}
+	if !reflect.DeepEqual(response, expectedResponse) {
+		t.Errorf("response entry = %#v, expected %#v", response, expectedResponse)
+	}
+}
+
+func TestStructuredLoggerErrorResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	logger := &recordingStructuredLogger{level: LevelWarn}
+	WithStructuredLogger(logger)(client)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/customers/1.json", client.pathPrefix),
+		httpmock.NewStringResponder(404, `{"errors":"Not Found"}`))
+
+	if _, err := client.Customer.Get(context.Background(), 1, nil); err == nil {
+		t.Fatal("Customer.Get should return error!")
+	}
+
+	// only the error response is logged at warn, without its body
+	if len(logger.entries) != 1 {
+		t.Fatalf("expected a single response entry, got %#v", logger.entries)
+	}
+	entry := logger.entries[0]
+	if entry.level != LevelWarn || entry.attrs["status"] != 404 || entry.attrs["body"] != nil {
+		t.Errorf("unexpected response entry %#v", entry)
+	}
+}
+
+func TestLeveledStructuredLogger(t *testing.T) {
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	logger := NewLeveledStructuredLogger(&LeveledLogger{Level: LevelInfo, stdoutOverride: out, stderrOverride: errOut})
+
+	if logger.Enabled(context.Background(), LevelDebug) {
+		t.Errorf("Enabled(LevelDebug) should be false for a LevelInfo logger")
+	}
+
+	logger.Log(context.Background(), LevelInfo, "shopify response", LogAttr{"status", 200}, LogAttr{"path", "/admin/shop.json"})
+	logger.Log(context.Background(), LevelWarn, "shopify response", LogAttr{"status", 404})
+	logger.Log(context.Background(), LevelError, "failed")
+	logger.Log(context.Background(), LevelDebug, "hidden")
+
+	if expected := "[INFO] shopify response status=200 path=/admin/shop.json\n"; out.String() != expected {
+		t.Errorf("stdout = %q, expected %q", out.String(), expected)
+	}
+	if expected := "[ERROR] failed\n"; !strings.HasSuffix(errOut.String(), expected) || !strings.HasPrefix(errOut.String(), "[WARN] shopify response status=404\n") {
+		t.Errorf("stderr = %q", errOut.String())
+	}
+}
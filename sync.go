@@ -0,0 +1,231 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ErrSyncStateNotFound is returned by a SyncStateStore when it has no state
+// for a sync.
+var ErrSyncStateNotFound = errors.New("sync state not found")
+
+// SyncState is the progress of a Syncer, saved after every sync.
+type SyncState struct {
+	// UpdatedAtMin is the mark of the sync: the latest updated_at synced so
+	// far.
+	UpdatedAtMin time.Time `json:"updated_at_min,omitempty"`
+
+	// Seen holds the updated_at of the resources emitted within the overlap
+	// of the mark, by id.
+	Seen map[uint64]time.Time `json:"seen,omitempty"`
+}
+
+// SyncStateStore stores the states of syncs by name. Implementations must be
+// safe for concurrent use.
+type SyncStateStore interface {
+	// LoadSyncState returns the sync's state or ErrSyncStateNotFound.
+	LoadSyncState(ctx context.Context, name string) (SyncState, error)
+	SaveSyncState(ctx context.Context, name string, state SyncState) error
+	DeleteSyncState(ctx context.Context, name string) error
+}
+
+// MemorySyncStateStore is a SyncStateStore keeping states in memory.
+type MemorySyncStateStore struct {
+	syncStateStore
+}
+
+// NewMemorySyncStateStore returns an empty MemorySyncStateStore.
+func NewMemorySyncStateStore() *MemorySyncStateStore {
+	return &MemorySyncStateStore{syncStateStore{newMemoryValueStore[SyncState](ErrSyncStateNotFound)}}
+}
+
+// FileSyncStateStore is a SyncStateStore keeping states in a JSON file.
+type FileSyncStateStore struct {
+	syncStateStore
+}
+
+// NewFileSyncStateStore returns a FileSyncStateStore using the file at path,
+// which is created on the first SaveSyncState.
+func NewFileSyncStateStore(path string) *FileSyncStateStore {
+	return &FileSyncStateStore{syncStateStore{newFileValueStore[SyncState](path, ErrSyncStateNotFound)}}
+}
+
+// syncStateStore implements SyncStateStore.
+type syncStateStore struct {
+	states *valueStore[SyncState]
+}
+
+// LoadSyncState implements SyncStateStore.
+func (s syncStateStore) LoadSyncState(_ context.Context, name string) (SyncState, error) {
+	return s.states.load(name)
+}
+
+// SaveSyncState implements SyncStateStore.
+func (s syncStateStore) SaveSyncState(_ context.Context, name string, state SyncState) error {
+	return s.states.save(name, state)
+}
+
+// DeleteSyncState implements SyncStateStore.
+func (s syncStateStore) DeleteSyncState(_ context.Context, name string) error {
+	return s.states.delete(name)
+}
+
+// ChangeType tells whether a synced resource was created or updated.
+type ChangeType string
+
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated"
+)
+
+// Change is a created or updated resource emitted by a Syncer.
+type Change[T any] struct {
+	Type      ChangeType
+	Id        uint64
+	UpdatedAt time.Time
+	Resource  T
+}
+
+const defaultSyncOverlap = 5 * time.Minute
+
+// Syncer polls a list endpoint for the resources changed since the previous
+// sync, tracking the latest updated_at seen as a high-water mark in a
+// SyncStateStore:
+//
+//	syncer := goshopify.NewSyncer("products", store, client.Product.ListWithPagination)
+//	err := syncer.Sync(ctx, func(change goshopify.Change[goshopify.Product]) error {
+//		return upsert(change.Resource)
+//	})
+//
+// Changes are queried again from Overlap before the mark, because a resource
+// may become visible to the API after resources updated later than it, and the
+// changes already emitted are suppressed by Id and UpdatedAt. A change is
+// Created when the resource was created within the overlap or after the mark
+// and wasn't emitted before, every change is Created on the first sync.
+//
+// The state is saved once every page was synced, a failed sync emits its
+// changes again on the next one.
+type Syncer[T any] struct {
+	// Name identifies the sync in the SyncStateStore.
+	Name string
+
+	Store SyncStateStore
+	List  ListFunc[T]
+
+	// Overlap is how long before the mark changes are queried again, defaults
+	// to 5 minutes.
+	Overlap time.Duration
+
+	// Options returns the options listing the resources updated since
+	// updatedAtMin, which is zero on the first sync. Defaults to a ListOptions
+	// of 250 resources ordered by updated_at, or for orders an
+	// OrderListOptions with Status "any", since Shopify only lists open
+	// orders by default. Custom options of orders must keep Status "any" for
+	// closed and cancelled orders to be synced.
+	Options func(updatedAtMin time.Time) interface{}
+
+	// Timestamps returns the id, created_at and updated_at of a resource.
+	// Defaults to the resource's Id, CreatedAt and UpdatedAt fields.
+	Timestamps func(resource T) (id uint64, createdAt, updatedAt time.Time)
+}
+
+// NewSyncer returns a Syncer of the resources listed by list, such as
+// ProductService.ListWithPagination.
+func NewSyncer[T any](name string, store SyncStateStore, list ListFunc[T]) *Syncer[T] {
+	return &Syncer[T]{Name: name, Store: store, List: list, Overlap: defaultSyncOverlap}
+}
+
+// Sync calls handler with every resource changed since the previous sync. An
+// error returned by handler stops the sync without moving the mark.
+func (s *Syncer[T]) Sync(ctx context.Context, handler func(Change[T]) error) error {
+	state, err := s.Store.LoadSyncState(ctx, s.Name)
+	if err != nil && !errors.Is(err, ErrSyncStateNotFound) {
+		return err
+	}
+
+	overlap := s.Overlap
+	if overlap < 0 {
+		overlap = 0
+	}
+
+	previousMark := state.UpdatedAtMin
+	var updatedAtMin time.Time
+	if !previousMark.IsZero() {
+		updatedAtMin = previousMark.Add(-overlap)
+	}
+
+	mark := previousMark
+	seen := make(map[uint64]time.Time, len(state.Seen))
+	for id, updatedAt := range state.Seen {
+		seen[id] = updatedAt
+	}
+
+	it := NewIterator(s.List, s.options(updatedAtMin))
+	for it.Next(ctx) {
+		resource := it.Value()
+		id, createdAt, updatedAt := s.timestamps(resource)
+
+		previous, emitted := seen[id]
+		if emitted && !updatedAt.After(previous) {
+			continue
+		}
+
+		change := Change[T]{Type: ChangeUpdated, Id: id, UpdatedAt: updatedAt, Resource: resource}
+		if !emitted && (previousMark.IsZero() || createdAt.After(updatedAtMin)) {
+			change.Type = ChangeCreated
+		}
+		if err := handler(change); err != nil {
+			return err
+		}
+
+		seen[id] = updatedAt
+		if updatedAt.After(mark) {
+			mark = updatedAt
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	// only the resources queried again by the next sync need to be remembered
+	for id, updatedAt := range seen {
+		if updatedAt.Before(mark.Add(-overlap)) {
+			delete(seen, id)
+		}
+	}
+
+	return s.Store.SaveSyncState(ctx, s.Name, SyncState{UpdatedAtMin: mark, Seen: seen})
+}
+
+func (s *Syncer[T]) options(updatedAtMin time.Time) interface{} {
+	if s.Options != nil {
+		return s.Options(updatedAtMin)
+	}
+
+	options := ListOptions{
+		Limit:        defaultExportPageSize,
+		UpdatedAtMin: updatedAtMin,
+		Order:        "updated_at asc",
+	}
+
+	var resource T
+	if _, ok := interface{}(resource).(Order); ok {
+		return &OrderListOptions{ListOptions: options, Status: OrderStatusAny}
+	}
+	return &options
+}
+
+func (s *Syncer[T]) timestamps(resource T) (uint64, time.Time, time.Time) {
+	if s.Timestamps != nil {
+		return s.Timestamps(resource)
+	}
+
+	id, updatedAt := resourceWatermark(resource)
+	createdAt := time.Time{}
+	if v := reflect.Indirect(reflect.ValueOf(resource)); v.Kind() == reflect.Struct {
+		createdAt = resourceTime(v, "CreatedAt")
+	}
+	return id, createdAt, updatedAt
+}
@@ -0,0 +1,201 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// fakeProducts lists the products updated since the updated_at_min of the options
+type fakeProducts struct {
+	products []Product
+	requests []ListOptions
+}
+
+func (f *fakeProducts) list(_ context.Context, options interface{}) ([]Product, *Pagination, error) {
+	listOptions := *options.(*ListOptions)
+	f.requests = append(f.requests, listOptions)
+
+	var products []Product
+	for _, product := range f.products {
+		if !product.UpdatedAt.Before(listOptions.UpdatedAtMin) {
+			products = append(products, product)
+		}
+	}
+	return products, new(Pagination), nil
+}
+
+func syncedProduct(id uint64, createdAt, updatedAt time.Time) Product {
+	return Product{Id: id, CreatedAt: &createdAt, UpdatedAt: &updatedAt}
+}
+
+func collectChanges(syncer *Syncer[Product]) ([]string, error) {
+	var changes []string
+	err := syncer.Sync(context.Background(), func(change Change[Product]) error {
+		changes = append(changes, fmt.Sprintf("%s %d", change.Type, change.Id))
+		return nil
+	})
+	return changes, err
+}
+
+func TestSyncer(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	db := &fakeProducts{products: []Product{
+		syncedProduct(1, t0, t0),
+		syncedProduct(2, t0, t0.Add(time.Minute)),
+	}}
+
+	store := NewMemorySyncStateStore()
+	syncer := NewSyncer("products", store, db.list)
+
+	// everything is created on the first sync
+	changes, err := collectChanges(syncer)
+	if err != nil {
+		t.Fatalf("Syncer.Sync returned error: %v", err)
+	}
+	if !reflect.DeepEqual(changes, []string{"created 1", "created 2"}) {
+		t.Errorf("first sync emitted %v", changes)
+	}
+	if !db.requests[0].UpdatedAtMin.IsZero() || db.requests[0].Order != "updated_at asc" {
+		t.Errorf("first sync requested %#v", db.requests[0])
+	}
+
+	// product 1 is updated, product 3 is created and product 4 shows up late
+	db.products[0] = syncedProduct(1, t0, t0.Add(3*time.Minute))
+	db.products = append(db.products,
+		syncedProduct(3, t0.Add(2*time.Minute), t0.Add(2*time.Minute)),
+		syncedProduct(4, t0.Add(-time.Minute), t0.Add(30*time.Second)))
+
+	changes, err = collectChanges(syncer)
+	if err != nil {
+		t.Fatalf("Syncer.Sync returned error: %v", err)
+	}
+	if !reflect.DeepEqual(changes, []string{"updated 1", "created 3", "created 4"}) {
+		t.Errorf("second sync emitted %v", changes)
+	}
+
+	// changes are queried again from the overlap before the mark
+	if expected := t0.Add(time.Minute).Add(-defaultSyncOverlap); !db.requests[1].UpdatedAtMin.Equal(expected) {
+		t.Errorf("second sync requested updated_at_min %v, expected %v", db.requests[1].UpdatedAtMin, expected)
+	}
+
+	// nothing changed
+	changes, err = collectChanges(syncer)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("third sync emitted %v, %v, expected nothing", changes, err)
+	}
+
+	state, _ := store.LoadSyncState(context.Background(), "products")
+	if !state.UpdatedAtMin.Equal(t0.Add(3 * time.Minute)) {
+		t.Errorf("mark = %v, expected %v", state.UpdatedAtMin, t0.Add(3*time.Minute))
+	}
+}
+
+func TestSyncerPrunesSeen(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	db := &fakeProducts{products: []Product{
+		syncedProduct(1, t0, t0),
+		syncedProduct(2, t0, t0.Add(time.Hour)),
+	}}
+
+	store := NewMemorySyncStateStore()
+	if _, err := collectChanges(NewSyncer("products", store, db.list)); err != nil {
+		t.Fatalf("Syncer.Sync returned error: %v", err)
+	}
+
+	state, _ := store.LoadSyncState(context.Background(), "products")
+	if !reflect.DeepEqual(state.Seen, map[uint64]time.Time{2: t0.Add(time.Hour)}) {
+		t.Errorf("seen = %v, expected only product 2", state.Seen)
+	}
+}
+
+func TestSyncerHandlerError(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	db := &fakeProducts{products: []Product{syncedProduct(1, t0, t0)}}
+
+	store := NewMemorySyncStateStore()
+	syncer := NewSyncer("products", store, db.list)
+
+	failure := errors.New("database down")
+	err := syncer.Sync(context.Background(), func(Change[Product]) error { return failure })
+	if err != failure {
+		t.Fatalf("Syncer.Sync returned %v, expected %v", err, failure)
+	}
+
+	// the change is emitted again
+	if _, err := store.LoadSyncState(context.Background(), "products"); !errors.Is(err, ErrSyncStateNotFound) {
+		t.Errorf("LoadSyncState returned %v, expected ErrSyncStateNotFound", err)
+	}
+	changes, _ := collectChanges(syncer)
+	if !reflect.DeepEqual(changes, []string{"created 1"}) {
+		t.Errorf("sync after failure emitted %v", changes)
+	}
+}
+
+func TestSyncerDraftOrders(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponderWithQuery("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/draft_orders.json", client.pathPrefix),
+		"limit=250&order=updated_at+asc",
+		httpmock.NewStringResponder(200, `{"draft_orders": [{"id":1,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}]}`))
+
+	syncer := NewSyncer("draft_orders", NewMemorySyncStateStore(), client.DraftOrder.ListWithPagination)
+
+	var changes []Change[DraftOrder]
+	err := syncer.Sync(context.Background(), func(change Change[DraftOrder]) error {
+		changes = append(changes, change)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Syncer.Sync returned error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Type != ChangeCreated || changes[0].Resource.Id != 1 ||
+		!changes[0].UpdatedAt.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Syncer.Sync emitted %#v", changes)
+	}
+}
+
+func TestSyncerClosedOrders(t *testing.T) {
+	setup()
+	defer teardown()
+
+	responses := []string{
+		`{"orders": [{"id":1,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}]}`,
+		`{"orders": [{"id":1,"closed_at":"2024-01-02T00:00:00Z","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z"}]}`,
+	}
+	calls := 0
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			// Shopify lists open orders only without status=any
+			if status := req.URL.Query().Get("status"); status != "any" {
+				t.Errorf("orders were listed with status %q, expected any", status)
+			}
+			resp := httpmock.NewStringResponse(200, responses[calls])
+			calls++
+			return resp, nil
+		})
+
+	syncer := NewSyncer("orders", NewMemorySyncStateStore(), client.Order.ListWithPagination)
+
+	var changes []Change[Order]
+	handler := func(change Change[Order]) error {
+		changes = append(changes, change)
+		return nil
+	}
+	for i := 0; i < 2; i++ {
+		if err := syncer.Sync(context.Background(), handler); err != nil {
+			t.Fatalf("Syncer.Sync returned error: %v", err)
+		}
+	}
+
+	if len(changes) != 2 || changes[1].Type != ChangeUpdated || changes[1].Resource.ClosedAt == nil {
+		t.Errorf("Syncer.Sync emitted %#v, expected the update of the closed order", changes)
+	}
+}
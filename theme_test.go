@@ -167,3 +167,18 @@ func TestThemeDelete(t *testing.T) {
 		t.Errorf("Theme.Delete returned error: %v", err)
 	}
 }
+
+func TestThemeGID(t *testing.T) {
+	theme := Theme{Id: 828155753, AdminGraphqlApiId: "gid://shopify/OnlineStoreTheme/828155753"}
+
+	gid, err := theme.GID()
+	if err != nil {
+		t.Fatalf("Theme.GID() returned error: %v", err)
+	}
+	if expected := NewThemeGID(theme.Id); *gid != *expected {
+		t.Errorf("Theme.GID() returned %v, expected %v", gid, expected)
+	}
+	if gid.String() != theme.AdminGraphqlApiId {
+		t.Errorf("NewThemeGID().String() = %s, expected %s", gid.String(), theme.AdminGraphqlApiId)
+	}
+}